@@ -1,6 +1,10 @@
 package mathutil
 
-import "math"
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
 
 // WithinNPercent returns true if a and b are within epsilon percent of one
 // another.  Strictly speaking the test is for whether the difference between
@@ -10,32 +14,207 @@ import "math"
 // proximity. The epsilon value is forced to a positive value (the absolute
 // value is taken).
 func WithinNPercent(a, b, epsilon float64) bool {
+	return WithinNPercentOf(a, b, epsilon, RelToMax, StraddleNeverClose)
+}
+
+// AlmostEqual returns true if a and b are within epsilon of one another
+// regardless of sign. The epsilon value is forced to a positive value (the
+// absolute value is taken).
+func AlmostEqual(a, b, epsilon float64) bool {
 	if a == b {
 		return true
 	}
 
-	absA := math.Abs(a)
-	absB := math.Abs(b)
+	return math.Abs(a-b) < math.Abs(epsilon)
+}
+
+// ulpOrder maps v onto a signed integer such that adjacent float values map
+// onto adjacent integers. Positive and negative zero both map onto zero.
+func ulpOrder[F constraints.Float](v F) int64 {
+	var i int64
+	if floatBitSize[F]() == float32Bits {
+		i = int64(int32(math.Float32bits(float32(v)))) //nolint:gosec
+		if i < 0 {
+			i = math.MinInt32 - i
+		}
+	} else {
+		i = int64(math.Float64bits(float64(v))) //nolint:gosec
+		if i < 0 {
+			i = math.MinInt64 - i
+		}
+	}
 
-	isPosA := (a == absA)
-	isPosB := (b == absB)
+	return i
+}
 
-	if isPosA != isPosB {
-		return false
+// UlpDistance returns the number of units in the last place (ULPs) between
+// a and b. That is, the number of distinct values of the float type lying
+// between them, plus one. Identical values (including positive and
+// negative zero) are zero ULPs apart and the largest finite value is one
+// ULP away from infinity. If either value is a NaN the maximum uint64 value
+// is returned.
+func UlpDistance[F constraints.Float](a, b F) uint64 {
+	if math.IsNaN(float64(a)) || math.IsNaN(float64(b)) {
+		return math.MaxUint64
 	}
 
-	pctDiff := ToPercent(math.Abs(absA-absB) / math.Max(absA, absB))
+	ia, ib := ulpOrder(a), ulpOrder(b)
+	if ia < ib {
+		ia, ib = ib, ia
+	}
 
-	return pctDiff <= math.Abs(epsilon)
+	return uint64(ia) - uint64(ib) //nolint:gosec
 }
 
-// AlmostEqual returns true if a and b are within epsilon of one another
-// regardless of sign. The epsilon value is forced to a positive value (the
-// absolute value is taken).
-func AlmostEqual(a, b, epsilon float64) bool {
+// AlmostEqualUlps returns true if a and b are no more than maxUlps units in
+// the last place apart. Unlike AlmostEqual this gives a tolerance which
+// scales with the magnitude of the values. NaN values are never equal.
+func AlmostEqualUlps[F constraints.Float](a, b F, maxUlps uint64) bool {
+	return UlpDistance(a, b) <= maxUlps
+}
+
+// NaNEquality determines whether IsClose treats NaN values as equal
+type NaNEquality int
+
+// These are the available NaNEquality values
+const (
+	// NaNNeverEqual treats a NaN as unequal to every value, including
+	// another NaN. This is the default.
+	NaNNeverEqual NaNEquality = iota
+	// NaNEqualsNaN treats a NaN as equal to another NaN (but to no other
+	// value).
+	NaNEqualsNaN
+)
+
+// InfEquality determines whether IsClose treats infinite values as equal
+type InfEquality int
+
+// These are the available InfEquality values
+const (
+	// InfEqualsSameInf treats an infinite value as equal to an infinite
+	// value of the same sign and to nothing else. This is the default.
+	InfEqualsSameInf InfEquality = iota
+	// InfNeverEqual treats an infinite value as unequal to every value,
+	// including itself.
+	InfNeverEqual
+)
+
+// DfltIsCloseRelTolPct is the default relative tolerance (as a percentage)
+// used by IsClose. It is equivalent to the default used by Python's
+// math.isclose.
+const DfltIsCloseRelTolPct = 1e-7
+
+// isCloseCfg holds the configuration for IsClose
+type isCloseCfg struct {
+	absTol float64
+	relTol float64
+	nan    NaNEquality
+	inf    InfEquality
+}
+
+// IsCloseOpt is an option that can be passed to IsClose to change the
+// tolerances or the treatment of NaN and infinite values.
+type IsCloseOpt func(*isCloseCfg)
+
+// IsCloseAbsTol returns an IsCloseOpt setting the absolute tolerance. This
+// is the minimum difference that is always considered close and is useful
+// when comparing values near zero. The default is zero. The tolerance is
+// forced to a positive value (the absolute value is taken).
+func IsCloseAbsTol(tol float64) IsCloseOpt {
+	return func(c *isCloseCfg) {
+		c.absTol = math.Abs(tol)
+	}
+}
+
+// IsCloseRelTolPct returns an IsCloseOpt setting the relative tolerance as a
+// percentage of the larger of the magnitudes of the values being
+// compared. The default is DfltIsCloseRelTolPct. The tolerance is forced to
+// a positive value (the absolute value is taken).
+func IsCloseRelTolPct(pct float64) IsCloseOpt {
+	return func(c *isCloseCfg) {
+		c.relTol = FromPercent(math.Abs(pct))
+	}
+}
+
+// IsCloseNaN returns an IsCloseOpt setting the treatment of NaN values
+func IsCloseNaN(ne NaNEquality) IsCloseOpt {
+	return func(c *isCloseCfg) {
+		c.nan = ne
+	}
+}
+
+// IsCloseInf returns an IsCloseOpt setting the treatment of infinite values
+func IsCloseInf(ie InfEquality) IsCloseOpt {
+	return func(c *isCloseCfg) {
+		c.inf = ie
+	}
+}
+
+// mkIsCloseCfg returns the IsClose configuration with the options applied
+func mkIsCloseCfg(opts []IsCloseOpt) isCloseCfg {
+	c := isCloseCfg{relTol: FromPercent(DfltIsCloseRelTolPct)}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	return c
+}
+
+// isClose performs the IsClose test using the given configuration
+func (c isCloseCfg) isClose(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return c.nan == NaNEqualsNaN && math.IsNaN(a) && math.IsNaN(b)
+	}
+
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return c.inf == InfEqualsSameInf && a == b
+	}
+
 	if a == b {
 		return true
 	}
 
-	return math.Abs(a-b) < math.Abs(epsilon)
+	diff := math.Abs(a - b)
+
+	return diff <= c.absTol ||
+		diff <= c.relTol*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// IsClose returns true if a and b are close to one another. They are close
+// if the difference between them is no greater than the absolute tolerance
+// or no greater than the relative tolerance times the larger of their
+// magnitudes. This combines the tests performed by AlmostEqual and
+// WithinNPercent in the same way as numpy's isclose or Python's
+// math.isclose. The tolerances and the treatment of NaN and infinite values
+// can be set through the options.
+func IsClose[F constraints.Float](a, b F, opts ...IsCloseOpt) bool {
+	return mkIsCloseCfg(opts).isClose(float64(a), float64(b))
+}
+
+// IsCloseFunc returns a function which will perform the IsClose test with
+// the given options. This avoids applying the options on every call.
+func IsCloseFunc[F constraints.Float](opts ...IsCloseOpt) func(a, b F) bool {
+	c := mkIsCloseCfg(opts)
+
+	return func(a, b F) bool {
+		return c.isClose(float64(a), float64(b))
+	}
+}
+
+// AlmostEqualFunc returns a function which will perform the AlmostEqual
+// test with the given epsilon. It can be used as the comparison function for
+// SlicesAlmostEqual and similar functions.
+func AlmostEqualFunc(epsilon float64) func(a, b float64) bool {
+	return func(a, b float64) bool {
+		return AlmostEqual(a, b, epsilon)
+	}
+}
+
+// WithinNPercentFunc returns a function which will perform the
+// WithinNPercent test with the given epsilon. It can be used as the
+// comparison function for SlicesAlmostEqual and similar functions.
+func WithinNPercentFunc(epsilon float64) func(a, b float64) bool {
+	return func(a, b float64) bool {
+		return WithinNPercent(a, b, epsilon)
+	}
 }
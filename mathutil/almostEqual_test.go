@@ -157,3 +157,192 @@ func TestWithinNPercent(t *testing.T) {
 			res, tc.expResult)
 	}
 }
+
+func TestUlpDistance(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		a, b       float64
+		expUlps    uint64
+		maxUlps    uint64
+		expAlmostE bool
+	}{
+		{
+			ID:         testhelper.MkID("identical"),
+			a:          1.0,
+			b:          1.0,
+			expUlps:    0,
+			expAlmostE: true,
+		},
+		{
+			ID:         testhelper.MkID("positive and negative zero"),
+			a:          0.0,
+			b:          math.Copysign(0, -1),
+			expUlps:    0,
+			expAlmostE: true,
+		},
+		{
+			ID:         testhelper.MkID("adjacent"),
+			a:          1.0,
+			b:          math.Nextafter(1.0, 2.0),
+			expUlps:    1,
+			maxUlps:    1,
+			expAlmostE: true,
+		},
+		{
+			ID:      testhelper.MkID("adjacent, maxUlps 0"),
+			a:       1.0,
+			b:       math.Nextafter(1.0, 2.0),
+			expUlps: 1,
+		},
+		{
+			ID:         testhelper.MkID("either side of zero"),
+			a:          math.SmallestNonzeroFloat64,
+			b:          -math.SmallestNonzeroFloat64,
+			expUlps:    2,
+			maxUlps:    2,
+			expAlmostE: true,
+		},
+		{
+			ID:         testhelper.MkID("max to infinity"),
+			a:          math.Inf(1),
+			b:          math.MaxFloat64,
+			expUlps:    1,
+			maxUlps:    4,
+			expAlmostE: true,
+		},
+		{
+			ID:      testhelper.MkID("NaN"),
+			a:       math.NaN(),
+			b:       math.NaN(),
+			expUlps: math.MaxUint64,
+			maxUlps: math.MaxUint64 - 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		ulps := mathutil.UlpDistance(tc.a, tc.b)
+		testhelper.DiffInt(t, tc.IDStr(), "ULPs", ulps, tc.expUlps)
+
+		ulps = mathutil.UlpDistance(tc.b, tc.a)
+		testhelper.DiffInt(t, tc.IDStr(), "ULPs (reversed)", ulps, tc.expUlps)
+
+		res := mathutil.AlmostEqualUlps(tc.a, tc.b, tc.maxUlps)
+		testhelper.DiffBool(t, tc.IDStr(), "AlmostEqualUlps",
+			res, tc.expAlmostE)
+	}
+
+	ulps := mathutil.UlpDistance(float32(1.0),
+		math.Nextafter32(math.Nextafter32(1.0, 2.0), 2.0))
+	testhelper.DiffInt(t, "float32", "ULPs", ulps, 2)
+}
+
+func TestIsClose(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		a, b      float64
+		opts      []mathutil.IsCloseOpt
+		expResult bool
+	}{
+		{
+			ID:        testhelper.MkID("identical"),
+			a:         1.5,
+			b:         1.5,
+			expResult: true,
+		},
+		{
+			ID:        testhelper.MkID("default relative tolerance, close"),
+			a:         1e10,
+			b:         1e10 + 1,
+			expResult: true,
+		},
+		{
+			ID:        testhelper.MkID("default relative tolerance, not close"),
+			a:         1e10,
+			b:         1e10 + 100,
+			expResult: false,
+		},
+		{
+			ID:        testhelper.MkID("near zero, no absolute tolerance"),
+			a:         1e-12,
+			b:         -1e-12,
+			expResult: false,
+		},
+		{
+			ID:        testhelper.MkID("near zero, absolute tolerance"),
+			a:         1e-12,
+			b:         -1e-12,
+			opts:      []mathutil.IsCloseOpt{mathutil.IsCloseAbsTol(1e-9)},
+			expResult: true,
+		},
+		{
+			ID:        testhelper.MkID("relative tolerance of 1%"),
+			a:         100,
+			b:         99,
+			opts:      []mathutil.IsCloseOpt{mathutil.IsCloseRelTolPct(1)},
+			expResult: true,
+		},
+		{
+			ID:        testhelper.MkID("NaN, default"),
+			a:         math.NaN(),
+			b:         math.NaN(),
+			expResult: false,
+		},
+		{
+			ID: testhelper.MkID("NaN, NaNs equal"),
+			a:  math.NaN(),
+			b:  math.NaN(),
+			opts: []mathutil.IsCloseOpt{
+				mathutil.IsCloseNaN(mathutil.NaNEqualsNaN),
+			},
+			expResult: true,
+		},
+		{
+			ID: testhelper.MkID("NaN and number, NaNs equal"),
+			a:  math.NaN(),
+			b:  1,
+			opts: []mathutil.IsCloseOpt{
+				mathutil.IsCloseNaN(mathutil.NaNEqualsNaN),
+			},
+			expResult: false,
+		},
+		{
+			ID:        testhelper.MkID("Inf, default"),
+			a:         math.Inf(1),
+			b:         math.Inf(1),
+			expResult: true,
+		},
+		{
+			ID: testhelper.MkID("Inf, opposite signs"),
+			a:  math.Inf(1),
+			b:  math.Inf(-1),
+			opts: []mathutil.IsCloseOpt{
+				mathutil.IsCloseAbsTol(math.Inf(1)),
+			},
+			expResult: false,
+		},
+		{
+			ID: testhelper.MkID("Inf, never equal"),
+			a:  math.Inf(1),
+			b:  math.Inf(1),
+			opts: []mathutil.IsCloseOpt{
+				mathutil.IsCloseInf(mathutil.InfNeverEqual),
+			},
+			expResult: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		res := mathutil.IsClose(tc.a, tc.b, tc.opts...)
+		testhelper.DiffBool(t, tc.IDStr(), "IsClose", res, tc.expResult)
+
+		f := mathutil.IsCloseFunc[float64](tc.opts...)
+		testhelper.DiffBool(t, tc.IDStr(), "IsCloseFunc",
+			f(tc.a, tc.b), tc.expResult)
+	}
+
+	f := mathutil.IsCloseFunc[float32](mathutil.IsCloseRelTolPct(1))
+	testhelper.DiffBool(t, "float32, close", "IsCloseFunc",
+		f(float32(100), float32(99.5)), true)
+	testhelper.DiffBool(t, "float32, not close", "IsCloseFunc",
+		f(float32(100), float32(98.5)), false)
+}
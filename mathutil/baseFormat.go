@@ -0,0 +1,415 @@
+package mathutil
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+const noDigit = -1
+
+var (
+	errParseNoDigits   = errors.New("there are no digits to parse")
+	errParseOutOfRange = errors.New("the value is out of range")
+	errParseNegUnsign  = errors.New("an unsigned value cannot be negative")
+)
+
+// Alphabet gives the characters used as digits when formatting and parsing
+// integers in a given base. The digit with value i is the i'th character of
+// the alphabet, so a base b uses the first b characters.
+type Alphabet struct {
+	digits string
+	vals   [256]int8
+}
+
+// NewAlphabet returns a pointer to a new Alphabet with the given digits.
+// If caseInsensitive is true then, when parsing, upper and lower case
+// letters are treated as the same digit. It will panic if there are fewer
+// than 2 or more than 64 digits, if any digit is not a printable ASCII
+// character (or is a sign character) or if any digit is repeated.
+func NewAlphabet(digits string, caseInsensitive bool) *Alphabet {
+	if len(digits) < minBase || len(digits) > maxAlphabetBase {
+		panic(fmt.Sprintf(
+			"Invalid alphabet (%q), it must have between %d and %d digits",
+			digits, minBase, maxAlphabetBase))
+	}
+
+	a := &Alphabet{digits: digits}
+	for i := range a.vals {
+		a.vals[i] = noDigit
+	}
+
+	for i := range len(digits) {
+		c := digits[i]
+		if c <= ' ' || c > '~' || c == '-' || c == '+' {
+			panic(fmt.Sprintf("Invalid alphabet (%q), bad digit: %q",
+				digits, c))
+		}
+
+		if a.vals[c] != noDigit {
+			panic(fmt.Sprintf("Invalid alphabet (%q), repeated digit: %q",
+				digits, c))
+		}
+
+		a.vals[c] = int8(i)
+	}
+
+	if caseInsensitive {
+		for i := range len(digits) {
+			a.alias(otherCase(digits[i]), digits[i])
+		}
+	}
+
+	return a
+}
+
+// otherCase returns the upper case form of a lower case ASCII letter and
+// vice versa. Any other character is returned unchanged.
+func otherCase(c byte) byte {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return c - 'a' + 'A'
+	case c >= 'A' && c <= 'Z':
+		return c - 'A' + 'a'
+	}
+
+	return c
+}
+
+// alias makes the character c parse as the digit d unless c is already a
+// digit
+func (a *Alphabet) alias(c, d byte) {
+	if a.vals[c] == noDigit {
+		a.vals[c] = a.vals[d]
+	}
+}
+
+// Base returns the number of digits in the alphabet, the largest base that
+// it can be used with
+func (a *Alphabet) Base() uint {
+	return uint(len(a.digits))
+}
+
+// String returns the digits of the alphabet
+func (a *Alphabet) String() string {
+	return a.digits
+}
+
+// mkCrockford32 returns Douglas Crockford's base 32 alphabet which avoids
+// the letters I, L, O and U. When parsing, I and L are read as 1 and O as 0.
+func mkCrockford32() *Alphabet {
+	a := NewAlphabet("0123456789ABCDEFGHJKMNPQRSTVWXYZ", true)
+
+	for _, c := range []byte("IiLl") {
+		a.alias(c, '1')
+	}
+
+	for _, c := range []byte("Oo") {
+		a.alias(c, '0')
+	}
+
+	return a
+}
+
+// These are some commonly used alphabets.
+var (
+	// AlphabetStd is the alphabet used by strconv; lower case letters are
+	// generated but either case is accepted.
+	AlphabetStd = NewAlphabet("0123456789abcdefghijklmnopqrstuvwxyz", true)
+	// AlphabetStdUpper is as AlphabetStd but generates upper case letters.
+	AlphabetStdUpper = NewAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+		true)
+	// AlphabetCrockford32 is Douglas Crockford's base 32 alphabet.
+	AlphabetCrockford32 = mkCrockford32()
+	// AlphabetBase58 is the base 58 alphabet used by Bitcoin, it omits
+	// characters that are easily confused (0, O, I and l).
+	AlphabetBase58 = NewAlphabet(
+		"123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", false)
+	// AlphabetBase62 uses the digits followed by the upper and then the
+	// lower case letters.
+	AlphabetBase62 = NewAlphabet(
+		"0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+		false)
+)
+
+// baseCfg holds the configuration for FormatInBase and ParseInBase
+type baseCfg struct {
+	alphabet  *Alphabet
+	zeroPad   int
+	fullWidth bool
+	width     int
+	groupSize int
+	groupSep  string
+	prefix    bool
+}
+
+// BaseOpt is an option that can be passed to FormatInBase and ParseInBase
+// to change how the value is formatted or parsed.
+type BaseOpt func(*baseCfg)
+
+// BaseAlphabet returns a BaseOpt which sets the alphabet used for the
+// digits. The default is AlphabetStd.
+func BaseAlphabet(a *Alphabet) BaseOpt {
+	return func(bc *baseCfg) {
+		bc.alphabet = a
+	}
+}
+
+// BaseZeroPad returns a BaseOpt which pads the formatted value with zeros
+// (the first digit of the alphabet) to at least n digits. It is ignored
+// when parsing.
+func BaseZeroPad(n int) BaseOpt {
+	return func(bc *baseCfg) {
+		bc.zeroPad = n
+	}
+}
+
+// BaseFullWidth returns a BaseOpt which pads the formatted value with zeros
+// to the number of digits needed for the value of largest magnitude of its
+// type, as given by DigitsInBase. So, for instance, every uint8 formatted in
+// base 2 has 8 digits and in base 16 has 2. It is ignored when parsing.
+func BaseFullWidth() BaseOpt {
+	return func(bc *baseCfg) {
+		bc.fullWidth = true
+	}
+}
+
+// BaseWidth returns a BaseOpt which pads the formatted value on the left
+// with spaces to at least n characters. When parsing, leading spaces are
+// ignored.
+func BaseWidth(n int) BaseOpt {
+	return func(bc *baseCfg) {
+		bc.width = n
+	}
+}
+
+// BaseGroup returns a BaseOpt which separates the digits of the formatted
+// value into groups of size digits, counting from the right, with the
+// separator between them. So, for instance, a group size of 4 and a
+// separator of "_" will show binary values in nibbles. When parsing, the
+// separator may appear anywhere between the digits. It will panic if the
+// size is not greater than zero.
+func BaseGroup(size int, sep string) BaseOpt {
+	if size <= 0 {
+		panic(fmt.Sprintf(
+			"Invalid group size (%d), it must be greater than zero", size))
+	}
+
+	return func(bc *baseCfg) {
+		bc.groupSize = size
+		bc.groupSep = sep
+	}
+}
+
+// BasePrefix returns a BaseOpt which adds the conventional prefix for the
+// base to the formatted value: "0b" for base 2, "0o" for base 8 and "0x" for
+// base 16. The prefix follows any sign. No prefix is added for other
+// bases. When parsing, the prefix (in either case) is removed if present.
+func BasePrefix() BaseOpt {
+	return func(bc *baseCfg) {
+		bc.prefix = true
+	}
+}
+
+// basePrefixes maps the bases having a conventional prefix to that prefix
+var basePrefixes = map[uint]string{
+	2:  "0b",
+	8:  "0o",
+	16: "0x",
+}
+
+// prefixFor returns the prefix to use for the base, if any
+func (bc baseCfg) prefixFor(b uint) string {
+	if !bc.prefix {
+		return ""
+	}
+
+	return basePrefixes[b]
+}
+
+// nDigits returns the number of digits that the magnitude will be formatted
+// with in base b, allowing for any padding. The maxMag is the magnitude of
+// the largest value of the type.
+func (bc baseCfg) nDigits(mag, maxMag uint64, b uint) int {
+	n := max(digitsUint64(mag, b), bc.zeroPad)
+	if bc.fullWidth {
+		n = max(n, digitsUint64(maxMag, b))
+	}
+
+	return n
+}
+
+// mkBaseCfg returns the configuration for the options having checked that
+// the base is valid for the alphabet. It will panic if not.
+func mkBaseCfg(b uint, opts []BaseOpt) baseCfg {
+	bc := baseCfg{alphabet: AlphabetStd}
+	for _, o := range opts {
+		o(&bc)
+	}
+
+	checkBaseRange(b, bc.alphabet.Base())
+
+	return bc
+}
+
+// isSigned returns true if the type T is a signed type
+func isSigned[T constraints.Integer]() bool {
+	return ^T(0) < 0
+}
+
+// maxMagnitude returns the absolute value of the value of T with the
+// largest magnitude
+func maxMagnitude[T constraints.Integer]() uint64 {
+	if isSigned[T]() {
+		return uint64(1) << (BitsOf[T]() - 1)
+	}
+
+	return uint64(^T(0))
+}
+
+// FormatInBase returns the string representation of v in base b. By default
+// it is the same as strconv would give but the digits, padding and grouping
+// can be changed through the options. The base must be at least 2 and no
+// more than the number of digits in the alphabet (36 for the default
+// alphabet); if not a panic is generated.
+func FormatInBase[T constraints.Integer](v T, b uint, opts ...BaseOpt) string {
+	bc := mkBaseCfg(b, opts)
+
+	mag, neg := magnitude(v)
+
+	nDigits := bc.nDigits(mag, maxMagnitude[T](), b)
+
+	// the digits are generated from the least significant
+	digits := make([]byte, 0, nDigits)
+	for range nDigits {
+		digits = append(digits, bc.alphabet.digits[mag%uint64(b)])
+		mag /= uint64(b)
+	}
+
+	slices.Reverse(digits)
+
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
+	}
+
+	sb.WriteString(bc.prefixFor(b))
+
+	for i, d := range digits {
+		if bc.groupSize > 0 && i > 0 && (nDigits-i)%bc.groupSize == 0 {
+			sb.WriteString(bc.groupSep)
+		}
+
+		sb.WriteByte(d)
+	}
+
+	s := sb.String()
+	if pad := bc.width - len(s); pad > 0 {
+		s = strings.Repeat(" ", pad) + s
+	}
+
+	return s
+}
+
+// ParseInBase returns the value represented by s in base b. The string may
+// start with a sign ('+' or '-'; a '-' is only allowed for a signed type).
+// The options should be the same as those used to format the value; the
+// alphabet gives the digits and any group separator is ignored, as are
+// leading spaces if a width is given. The base must be at least 2 and no
+// more than the number of digits in the alphabet; if not a panic is
+// generated.
+//
+// A non-nil error is returned if s has no digits, if any character is not a
+// valid digit in the base or if the value is out of range for the type.
+func ParseInBase[T constraints.Integer](s string, b uint, opts ...BaseOpt) (
+	T, error,
+) {
+	bc := mkBaseCfg(b, opts)
+
+	if bc.width > 0 {
+		s = strings.TrimLeft(s, " ")
+	}
+
+	neg := false
+
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	if neg && !isSigned[T]() {
+		return 0, errParseNegUnsign
+	}
+
+	if pfx := bc.prefixFor(b); pfx != "" &&
+		len(s) >= len(pfx) && strings.EqualFold(s[:len(pfx)], pfx) {
+		s = s[len(pfx):]
+	}
+
+	if bc.groupSize > 0 && bc.groupSep != "" {
+		s = strings.ReplaceAll(s, bc.groupSep, "")
+	}
+
+	if s == "" {
+		return 0, errParseNoDigits
+	}
+
+	var (
+		mag uint64
+		ok  = true
+	)
+
+	for i := range len(s) {
+		d := bc.alphabet.vals[s[i]]
+		if d == noDigit || uint(d) >= b {
+			return 0, fmt.Errorf("invalid digit (%q) for base %d", s[i], b)
+		}
+
+		if mag, ok = appendDigit(mag, uint64(d), b); !ok {
+			return 0, errParseOutOfRange
+		}
+	}
+
+	return fromMagnitude[T](mag, neg)
+}
+
+// appendDigit returns the value of mag with the digit d appended to it in
+// base b and true. If the result does not fit in a uint64 it returns false.
+func appendDigit(mag, d uint64, b uint) (uint64, bool) {
+	hi, lo := bits.Mul64(mag, uint64(b))
+	if hi != 0 {
+		return 0, false
+	}
+
+	mag, carry := bits.Add64(lo, d, 0)
+
+	return mag, carry == 0
+}
+
+// fromMagnitude returns the value of type T with the magnitude mag, negated
+// if neg is true. It returns a non-nil error if the value does not fit in
+// the type.
+func fromMagnitude[T constraints.Integer](mag uint64, neg bool) (T, error) {
+	if neg && !isSigned[T]() && mag != 0 {
+		return 0, errParseNegUnsign
+	}
+
+	limit := maxMagnitude[T]()
+	if isSigned[T]() && !neg {
+		limit--
+	}
+
+	if mag > limit {
+		return 0, errParseOutOfRange
+	}
+
+	if neg {
+		return T(-int64(mag-1) - 1), nil
+	}
+
+	return T(mag), nil
+}
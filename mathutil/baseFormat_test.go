@@ -0,0 +1,355 @@
+package mathutil_test
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFormatInBase(t *testing.T) {
+	upper := mathutil.BaseAlphabet(mathutil.AlphabetStdUpper)
+	crockford := mathutil.BaseAlphabet(mathutil.AlphabetCrockford32)
+	base58 := mathutil.BaseAlphabet(mathutil.AlphabetBase58)
+	base62 := mathutil.BaseAlphabet(mathutil.AlphabetBase62)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		v      int64
+		base   uint
+		opts   []mathutil.BaseOpt
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("zero"),
+			v:      0,
+			base:   10,
+			expStr: "0",
+		},
+		{
+			ID:     testhelper.MkID("hex"),
+			v:      255,
+			base:   16,
+			expStr: "ff",
+		},
+		{
+			ID:     testhelper.MkID("hex, upper case"),
+			v:      -255,
+			base:   16,
+			opts:   []mathutil.BaseOpt{upper},
+			expStr: "-FF",
+		},
+		{
+			ID:     testhelper.MkID("MinInt64, base 36"),
+			v:      math.MinInt64,
+			base:   36,
+			expStr: "-1y2p0ij32e8e8",
+		},
+		{
+			ID:     testhelper.MkID("zero padded"),
+			v:      -42,
+			base:   10,
+			opts:   []mathutil.BaseOpt{mathutil.BaseZeroPad(5)},
+			expStr: "-00042",
+		},
+		{
+			ID:   testhelper.MkID("binary nibbles"),
+			v:    0xa5,
+			base: 2,
+			opts: []mathutil.BaseOpt{
+				mathutil.BaseZeroPad(12),
+				mathutil.BaseGroup(4, "_"),
+			},
+			expStr: "0000_1010_0101",
+		},
+		{
+			ID:     testhelper.MkID("octal triples"),
+			v:      01234567,
+			base:   8,
+			opts:   []mathutil.BaseOpt{mathutil.BaseGroup(3, " ")},
+			expStr: "1 234 567",
+		},
+		{
+			ID:   testhelper.MkID("width"),
+			v:    -1234567,
+			base: 10,
+			opts: []mathutil.BaseOpt{
+				mathutil.BaseGroup(3, ","),
+				mathutil.BaseWidth(12),
+			},
+			expStr: "  -1,234,567",
+		},
+		{
+			ID:     testhelper.MkID("full width"),
+			v:      255,
+			base:   16,
+			opts:   []mathutil.BaseOpt{mathutil.BaseFullWidth()},
+			expStr: "00000000000000ff",
+		},
+		{
+			ID:     testhelper.MkID("Crockford base 32"),
+			v:      1234567890,
+			base:   32,
+			opts:   []mathutil.BaseOpt{crockford},
+			expStr: "14SC0PJ",
+		},
+		{
+			ID:     testhelper.MkID("base 58"),
+			v:      0,
+			base:   58,
+			opts:   []mathutil.BaseOpt{base58},
+			expStr: "1",
+		},
+		{
+			ID:     testhelper.MkID("base 58, 57"),
+			v:      57 * 58,
+			base:   58,
+			opts:   []mathutil.BaseOpt{base58},
+			expStr: "z1",
+		},
+		{
+			ID:     testhelper.MkID("base 62"),
+			v:      61*62 + 36,
+			base:   62,
+			opts:   []mathutil.BaseOpt{base62},
+			expStr: "za",
+		},
+		{
+			ID: testhelper.MkID("base too big"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid base (37), the base must be no more than 36"),
+			v:    1,
+			base: 37,
+		},
+		{
+			ID: testhelper.MkID("base too big for the alphabet"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid base (59), the base must be no more than 58"),
+			v:    1,
+			base: 59,
+			opts: []mathutil.BaseOpt{base58},
+		},
+		{
+			ID: testhelper.MkID("base too small"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid base (1), the base must be at least 2"),
+			v:    1,
+			base: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			s := mathutil.FormatInBase(tc.v, tc.base, tc.opts...)
+			testhelper.DiffString(t, tc.IDStr(), "formatted value",
+				s, tc.expStr)
+
+			v, err := mathutil.ParseInBase[int64](s, tc.base, tc.opts...)
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: unexpected error parsing %q: %v", s, err)
+
+				return
+			}
+
+			testhelper.DiffInt(t, tc.IDStr(), "parsed value", v, tc.v)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+func TestFormatInBaseTypes(t *testing.T) {
+	fullBin := []mathutil.BaseOpt{
+		mathutil.BaseFullWidth(),
+		mathutil.BaseGroup(4, "_"),
+	}
+
+	testhelper.DiffString(t, "uint8", "binary",
+		mathutil.FormatInBase(uint8(5), 2, fullBin...), "0000_0101")
+	testhelper.DiffString(t, "int8", "binary",
+		mathutil.FormatInBase(int8(math.MinInt8), 2, fullBin...),
+		"-1000_0000")
+	testhelper.DiffString(t, "uint16", "hex",
+		mathutil.FormatInBase(uint16(0xbeef), 16, mathutil.BaseFullWidth()),
+		"beef")
+	testhelper.DiffString(t, "uint64", "max",
+		mathutil.FormatInBase(uint64(math.MaxUint64), 10),
+		strconv.FormatUint(math.MaxUint64, 10))
+
+	for b := uint(2); b <= 36; b++ {
+		for _, v := range []int64{math.MinInt64, -1, 0, 1, math.MaxInt64} {
+			testhelper.DiffString(t, "int64", "strconv equivalence",
+				mathutil.FormatInBase(v, b), strconv.FormatInt(v, int(b)))
+		}
+	}
+}
+
+func TestParseInBase(t *testing.T) {
+	crockford := mathutil.BaseAlphabet(mathutil.AlphabetCrockford32)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		s      string
+		base   uint
+		opts   []mathutil.BaseOpt
+		expVal int8
+	}{
+		{
+			ID:     testhelper.MkID("simple"),
+			s:      "127",
+			base:   10,
+			expVal: 127,
+		},
+		{
+			ID:     testhelper.MkID("plus sign"),
+			s:      "+7f",
+			base:   16,
+			expVal: 127,
+		},
+		{
+			ID:     testhelper.MkID("either case"),
+			s:      "-7F",
+			base:   16,
+			expVal: -127,
+		},
+		{
+			ID:     testhelper.MkID("min value"),
+			s:      "-128",
+			base:   10,
+			expVal: -128,
+		},
+		{
+			ID:     testhelper.MkID("Crockford aliases"),
+			s:      "iO",
+			base:   32,
+			opts:   []mathutil.BaseOpt{crockford},
+			expVal: 32,
+		},
+		{
+			ID:     testhelper.MkID("grouped"),
+			s:      "0111_1111",
+			base:   2,
+			opts:   []mathutil.BaseOpt{mathutil.BaseGroup(4, "_")},
+			expVal: 127,
+		},
+		{
+			ID:     testhelper.MkID("too big"),
+			ExpErr: testhelper.MkExpErr("the value is out of range"),
+			s:      "128",
+			base:   10,
+		},
+		{
+			ID:     testhelper.MkID("too small"),
+			ExpErr: testhelper.MkExpErr("the value is out of range"),
+			s:      "-129",
+			base:   10,
+		},
+		{
+			ID:     testhelper.MkID("overflows uint64"),
+			ExpErr: testhelper.MkExpErr("the value is out of range"),
+			s:      "99999999999999999999999",
+			base:   10,
+		},
+		{
+			ID:     testhelper.MkID("bad digit"),
+			ExpErr: testhelper.MkExpErr(`invalid digit ('8') for base 8`),
+			s:      "18",
+			base:   8,
+		},
+		{
+			ID:     testhelper.MkID("separator without the option"),
+			ExpErr: testhelper.MkExpErr(`invalid digit ('_') for base 2`),
+			s:      "1_1",
+			base:   2,
+		},
+		{
+			ID:     testhelper.MkID("empty"),
+			ExpErr: testhelper.MkExpErr("there are no digits to parse"),
+			s:      "",
+			base:   10,
+		},
+		{
+			ID:     testhelper.MkID("sign only"),
+			ExpErr: testhelper.MkExpErr("there are no digits to parse"),
+			s:      "-",
+			base:   10,
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := mathutil.ParseInBase[int8](tc.s, tc.base, tc.opts...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffInt(t, tc.IDStr(), "value", v, tc.expVal)
+		}
+	}
+}
+
+func TestParseInBaseUnsigned(t *testing.T) {
+	v, err := mathutil.ParseInBase[uint64]("ffffffffffffffff", 16)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "ParseInBase", "MaxUint64", v, math.MaxUint64)
+
+	_, err = mathutil.ParseInBase[uint64]("10000000000000000", 16)
+	if err == nil {
+		t.Error("ParseInBase: MaxUint64+1: an error was expected")
+	}
+
+	_, err = mathutil.ParseInBase[uint]("-1", 10)
+	if err == nil {
+		t.Error("ParseInBase: negative unsigned: an error was expected")
+	}
+
+	u8, err := mathutil.ParseInBase[uint8]("  ff", 16, mathutil.BaseWidth(4))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "ParseInBase", "padded uint8", u8, 255)
+}
+
+func TestNewAlphabet(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		digits string
+	}{
+		{
+			ID:     testhelper.MkID("good"),
+			digits: "01",
+		},
+		{
+			ID: testhelper.MkID("too short"),
+			ExpPanic: testhelper.MkExpPanic(`Invalid alphabet ("0"),` +
+				" it must have between 2 and 64 digits"),
+			digits: "0",
+		},
+		{
+			ID: testhelper.MkID("repeated"),
+			ExpPanic: testhelper.MkExpPanic(`Invalid alphabet ("010"),` +
+				` repeated digit: '0'`),
+			digits: "010",
+		},
+		{
+			ID: testhelper.MkID("sign"),
+			ExpPanic: testhelper.MkExpPanic(`Invalid alphabet ("0-"),` +
+				` bad digit: '-'`),
+			digits: "0-",
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			a := mathutil.NewAlphabet(tc.digits, false)
+			testhelper.DiffInt(t, tc.IDStr(), "base",
+				a.Base(), uint(len(tc.digits)))
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
@@ -0,0 +1,204 @@
+package mathutil
+
+import (
+	"fmt"
+	"math/bits"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Bit widths of the integer types
+const (
+	width8  = 8
+	width16 = 16
+	width32 = 32
+	width64 = 64
+)
+
+// widthMask returns a uint64 with the lowest BitsOf[T]() bits set
+func widthMask[T constraints.Integer]() uint64 {
+	return ^uint64(0) >> (width64 - BitsOf[T]())
+}
+
+// bitPattern returns the bits of v as an unsigned value; a negative value
+// is not sign-extended beyond the width of T
+func bitPattern[T constraints.Integer](v T) uint64 {
+	return uint64(v) & widthMask[T]() //nolint:gosec
+}
+
+// checkBitIndex panics if k is not a valid bit index for the type T
+func checkBitIndex[T constraints.Integer](k uint) {
+	if w := BitsOf[T](); k >= uint(w) {
+		panic(fmt.Sprintf(
+			"Invalid bit index (%d), it must be less than %d", k, w))
+	}
+}
+
+// BitLen returns the number of bits needed to represent v; the bits of v
+// are treated as unsigned so any negative value needs BitsOf[T]() bits.
+// The result for zero is zero.
+func BitLen[T constraints.Integer](v T) int {
+	switch BitsOf[T]() {
+	case width8:
+		return bits.Len8(uint8(v))
+	case width16:
+		return bits.Len16(uint16(v))
+	case width32:
+		return bits.Len32(uint32(v))
+	}
+
+	return bits.Len64(uint64(v))
+}
+
+// OnesCount returns the number of bits set in v. For a negative value this
+// includes the sign bit.
+func OnesCount[T constraints.Integer](v T) int {
+	switch BitsOf[T]() {
+	case width8:
+		return bits.OnesCount8(uint8(v))
+	case width16:
+		return bits.OnesCount16(uint16(v))
+	case width32:
+		return bits.OnesCount32(uint32(v))
+	}
+
+	return bits.OnesCount64(uint64(v))
+}
+
+// LeadingZeros returns the number of leading zero bits in v; the result is
+// BitsOf[T]() for zero.
+func LeadingZeros[T constraints.Integer](v T) int {
+	return BitsOf[T]() - BitLen(v)
+}
+
+// TrailingZeros returns the number of trailing zero bits in v; the result
+// is BitsOf[T]() for zero.
+func TrailingZeros[T constraints.Integer](v T) int {
+	switch BitsOf[T]() {
+	case width8:
+		return bits.TrailingZeros8(uint8(v))
+	case width16:
+		return bits.TrailingZeros16(uint16(v))
+	case width32:
+		return bits.TrailingZeros32(uint32(v))
+	}
+
+	return bits.TrailingZeros64(uint64(v))
+}
+
+// RotateLeft returns the value of v with its bits rotated left by k
+// places. To rotate right by k places, pass -k.
+func RotateLeft[T constraints.Integer](v T, k int) T {
+	switch BitsOf[T]() {
+	case width8:
+		return T(bits.RotateLeft8(uint8(v), k))
+	case width16:
+		return T(bits.RotateLeft16(uint16(v), k))
+	case width32:
+		return T(bits.RotateLeft32(uint32(v), k))
+	}
+
+	return T(bits.RotateLeft64(uint64(v), k))
+}
+
+// ReverseBits returns the value of v with its bits in the reverse order.
+func ReverseBits[T constraints.Integer](v T) T {
+	switch BitsOf[T]() {
+	case width8:
+		return T(bits.Reverse8(uint8(v)))
+	case width16:
+		return T(bits.Reverse16(uint16(v)))
+	case width32:
+		return T(bits.Reverse32(uint32(v)))
+	}
+
+	return T(bits.Reverse64(uint64(v)))
+}
+
+// TestBit returns true if bit k of v is set; bit zero is the least
+// significant. Note that k must be less than BitsOf[T](); if not a panic
+// is generated.
+func TestBit[T constraints.Integer](v T, k uint) bool {
+	checkBitIndex[T](k)
+
+	return bitPattern(v)&(1<<k) != 0
+}
+
+// SetBit returns the value of v with bit k set. Note that k must be less
+// than BitsOf[T](); if not a panic is generated.
+func SetBit[T constraints.Integer](v T, k uint) T {
+	checkBitIndex[T](k)
+
+	return T(bitPattern(v) | 1<<k)
+}
+
+// ClearBit returns the value of v with bit k cleared. Note that k must be
+// less than BitsOf[T](); if not a panic is generated.
+func ClearBit[T constraints.Integer](v T, k uint) T {
+	checkBitIndex[T](k)
+
+	return T(bitPattern(v) &^ (1 << k))
+}
+
+// FlipBit returns the value of v with bit k inverted. Note that k must be
+// less than BitsOf[T](); if not a panic is generated.
+func FlipBit[T constraints.Integer](v T, k uint) T {
+	checkBitIndex[T](k)
+
+	return T(bitPattern(v) ^ 1<<k)
+}
+
+// fieldMask returns the mask for a bit field of n bits starting at bit lo
+// having checked that the field fits in the type T. It will panic if not.
+func fieldMask[T constraints.Integer](lo, n uint) uint64 {
+	if w := uint(BitsOf[T]()); lo+n > w || lo+n < lo {
+		panic(fmt.Sprintf(
+			"Invalid bit field (%d bits from bit %d), it must fit in %d bits",
+			n, lo, w))
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return ^uint64(0) >> (width64 - n) << lo
+}
+
+// ExtractBits returns the n bits of v starting at bit lo, shifted down so
+// that bit lo of v is bit zero of the result. Note that the field must fit
+// within BitsOf[T]() bits; if not a panic is generated.
+func ExtractBits[T constraints.Integer](v T, lo, n uint) T {
+	mask := fieldMask[T](lo, n)
+
+	return T((bitPattern(v) & mask) >> lo)
+}
+
+// InsertBits returns the value of v with the n bits starting at bit lo
+// replaced by the lowest n bits of field. Note that the field must fit
+// within BitsOf[T]() bits; if not a panic is generated.
+func InsertBits[T constraints.Integer](v T, lo, n uint, field T) T {
+	mask := fieldMask[T](lo, n)
+
+	return T(bitPattern(v)&^mask | (bitPattern(field)<<lo)&mask)
+}
+
+// IsPowerOfTwo returns true if v is a positive power of two.
+func IsPowerOfTwo[T constraints.Integer](v T) bool {
+	return v > 0 && v&(v-1) == 0
+}
+
+// NextPowerOfTwo returns the smallest power of two which is greater than or
+// equal to v; for any value less than 2 this is 1. A non-nil error is
+// returned if the result is out of range for the type.
+func NextPowerOfTwo[T constraints.Integer](v T) (T, error) {
+	if v <= 1 {
+		return 1, nil
+	}
+
+	p := T(1) << BitLen(v-1)
+	if p <= 0 {
+		return 0, errParseOutOfRange
+	}
+
+	return p, nil
+}
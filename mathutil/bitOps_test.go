@@ -0,0 +1,306 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestBitCounts(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		v        int8
+		expLen   int
+		expOnes  int
+		expLead  int
+		expTrail int
+	}{
+		{
+			ID:       testhelper.MkID("zero"),
+			v:        0,
+			expLen:   0,
+			expOnes:  0,
+			expLead:  8,
+			expTrail: 8,
+		},
+		{
+			ID:       testhelper.MkID("one"),
+			v:        1,
+			expLen:   1,
+			expOnes:  1,
+			expLead:  7,
+			expTrail: 0,
+		},
+		{
+			ID:       testhelper.MkID("twelve"),
+			v:        12,
+			expLen:   4,
+			expOnes:  2,
+			expLead:  4,
+			expTrail: 2,
+		},
+		{
+			ID:       testhelper.MkID("minus one"),
+			v:        -1,
+			expLen:   8,
+			expOnes:  8,
+			expLead:  0,
+			expTrail: 0,
+		},
+		{
+			ID:       testhelper.MkID("MinInt8"),
+			v:        math.MinInt8,
+			expLen:   8,
+			expOnes:  1,
+			expLead:  0,
+			expTrail: 7,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffInt(t, tc.IDStr(), "BitLen",
+			mathutil.BitLen(tc.v), tc.expLen)
+		testhelper.DiffInt(t, tc.IDStr(), "OnesCount",
+			mathutil.OnesCount(tc.v), tc.expOnes)
+		testhelper.DiffInt(t, tc.IDStr(), "LeadingZeros",
+			mathutil.LeadingZeros(tc.v), tc.expLead)
+		testhelper.DiffInt(t, tc.IDStr(), "TrailingZeros",
+			mathutil.TrailingZeros(tc.v), tc.expTrail)
+	}
+
+	testhelper.DiffInt(t, "OnesCount", "int64(-1)",
+		mathutil.OnesCount(int64(-1)), 64)
+	testhelper.DiffInt(t, "LeadingZeros", "uint32(1)",
+		mathutil.LeadingZeros(uint32(1)), 31)
+	testhelper.DiffInt(t, "TrailingZeros", "uint16(0)",
+		mathutil.TrailingZeros(uint16(0)), 16)
+}
+
+func TestRotateAndReverse(t *testing.T) {
+	testhelper.DiffInt(t, "RotateLeft", "uint8",
+		mathutil.RotateLeft(uint8(0x81), 1), 0x03)
+	testhelper.DiffInt(t, "RotateLeft", "uint8, right",
+		mathutil.RotateLeft(uint8(0x81), -1), 0xc0)
+	testhelper.DiffInt(t, "RotateLeft", "int8",
+		mathutil.RotateLeft(int8(math.MinInt8), 1), 1)
+	testhelper.DiffInt(t, "RotateLeft", "uint32",
+		mathutil.RotateLeft(uint32(0xf0000000), 4), 0xf)
+	testhelper.DiffInt(t, "RotateLeft", "int64",
+		mathutil.RotateLeft(int64(1), -1), math.MinInt64)
+
+	testhelper.DiffInt(t, "ReverseBits", "uint8",
+		mathutil.ReverseBits(uint8(0x01)), 0x80)
+	testhelper.DiffInt(t, "ReverseBits", "int16",
+		mathutil.ReverseBits(int16(1)), math.MinInt16)
+	testhelper.DiffInt(t, "ReverseBits", "uint64",
+		mathutil.ReverseBits(uint64(0x0f)), 0xf000000000000000)
+}
+
+func TestSingleBits(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		v        int8
+		k        uint
+		expTest  bool
+		expSet   int8
+		expClear int8
+		expFlip  int8
+	}{
+		{
+			ID:       testhelper.MkID("bit 0 of 0"),
+			v:        0,
+			k:        0,
+			expTest:  false,
+			expSet:   1,
+			expClear: 0,
+			expFlip:  1,
+		},
+		{
+			ID:       testhelper.MkID("bit 2 of 5"),
+			v:        5,
+			k:        2,
+			expTest:  true,
+			expSet:   5,
+			expClear: 1,
+			expFlip:  1,
+		},
+		{
+			ID:       testhelper.MkID("sign bit"),
+			v:        -1,
+			k:        7,
+			expTest:  true,
+			expSet:   -1,
+			expClear: math.MaxInt8,
+			expFlip:  math.MaxInt8,
+		},
+		{
+			ID: testhelper.MkID("bad index"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid bit index (8), it must be less than 8"),
+			v: 0,
+			k: 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			testhelper.DiffBool(t, tc.IDStr(), "TestBit",
+				mathutil.TestBit(tc.v, tc.k), tc.expTest)
+			testhelper.DiffInt(t, tc.IDStr(), "SetBit",
+				mathutil.SetBit(tc.v, tc.k), tc.expSet)
+			testhelper.DiffInt(t, tc.IDStr(), "ClearBit",
+				mathutil.ClearBit(tc.v, tc.k), tc.expClear)
+			testhelper.DiffInt(t, tc.IDStr(), "FlipBit",
+				mathutil.FlipBit(tc.v, tc.k), tc.expFlip)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+func TestBitFields(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		v          uint16
+		lo, n      uint
+		field      uint16
+		expExtract uint16
+		expInsert  uint16
+	}{
+		{
+			ID:         testhelper.MkID("middle nibble"),
+			v:          0xabcd,
+			lo:         4,
+			n:          4,
+			field:      0x1,
+			expExtract: 0xc,
+			expInsert:  0xab1d,
+		},
+		{
+			ID:         testhelper.MkID("field too big for n"),
+			v:          0xabcd,
+			lo:         8,
+			n:          4,
+			field:      0xff,
+			expExtract: 0xb,
+			expInsert:  0xafcd,
+		},
+		{
+			ID:         testhelper.MkID("whole value"),
+			v:          0xabcd,
+			lo:         0,
+			n:          16,
+			field:      0x1234,
+			expExtract: 0xabcd,
+			expInsert:  0x1234,
+		},
+		{
+			ID:         testhelper.MkID("empty field"),
+			v:          0xabcd,
+			lo:         16,
+			n:          0,
+			field:      0x1234,
+			expExtract: 0,
+			expInsert:  0xabcd,
+		},
+		{
+			ID: testhelper.MkID("too wide"),
+			ExpPanic: testhelper.MkExpPanic("Invalid bit field" +
+				" (4 bits from bit 13), it must fit in 16 bits"),
+			v:  0xabcd,
+			lo: 13,
+			n:  4,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			testhelper.DiffInt(t, tc.IDStr(), "ExtractBits",
+				mathutil.ExtractBits(tc.v, tc.lo, tc.n), tc.expExtract)
+			testhelper.DiffInt(t, tc.IDStr(), "InsertBits",
+				mathutil.InsertBits(tc.v, tc.lo, tc.n, tc.field),
+				tc.expInsert)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+
+	testhelper.DiffInt(t, "ExtractBits", "negative int8",
+		mathutil.ExtractBits(int8(-2), 4, 4), 0xf)
+	testhelper.DiffInt(t, "InsertBits", "into the sign bit",
+		mathutil.InsertBits(int8(0), 7, 1, 1), math.MinInt8)
+}
+
+func TestPowersOfTwo(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		v       int16
+		expIs   bool
+		expNext int16
+	}{
+		{
+			ID:      testhelper.MkID("negative"),
+			v:       -4,
+			expNext: 1,
+		},
+		{
+			ID:      testhelper.MkID("zero"),
+			v:       0,
+			expNext: 1,
+		},
+		{
+			ID:      testhelper.MkID("one"),
+			v:       1,
+			expIs:   true,
+			expNext: 1,
+		},
+		{
+			ID:      testhelper.MkID("three"),
+			v:       3,
+			expNext: 4,
+		},
+		{
+			ID:      testhelper.MkID("1024"),
+			v:       1024,
+			expIs:   true,
+			expNext: 1024,
+		},
+		{
+			ID:      testhelper.MkID("largest"),
+			v:       1 << 14,
+			expIs:   true,
+			expNext: 1 << 14,
+		},
+		{
+			ID:     testhelper.MkID("too big"),
+			ExpErr: testhelper.MkExpErr("the value is out of range"),
+			v:      1<<14 + 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffBool(t, tc.IDStr(), "IsPowerOfTwo",
+			mathutil.IsPowerOfTwo(tc.v), tc.expIs)
+
+		next, err := mathutil.NextPowerOfTwo(tc.v)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffInt(t, tc.IDStr(), "NextPowerOfTwo",
+				next, tc.expNext)
+		}
+	}
+
+	next, err := mathutil.NextPowerOfTwo(uint64(1<<63 - 1))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "NextPowerOfTwo", "uint64", next, 1<<63)
+
+	_, err = mathutil.NextPowerOfTwo(uint8(129))
+	if err == nil {
+		t.Error("NextPowerOfTwo: uint8(129): an error was expected")
+	}
+}
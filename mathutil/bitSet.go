@@ -0,0 +1,523 @@
+package mathutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// bitSetVersion is the version of the binary encoding of a BitSet
+const bitSetVersion = 1
+
+// bitSetFixedFlag is set in the flags byte of the binary encoding of a
+// fixed-length BitSet
+const bitSetFixedFlag = 1
+
+// wordBytes is the number of bytes in each word of a BitSet
+const wordBytes = width64 / bitsInByte
+
+var (
+	errBitSetBadVersion = errors.New("unknown BitSet encoding version")
+	errBitSetTruncated  = errors.New("the BitSet encoding is truncated")
+	errBitSetBadEncode  = errors.New("the BitSet encoding is invalid")
+)
+
+// BitSet is a set of non-negative integers, each held as a single bit in a
+// slice of uint64 words. The length of the set is the number of bits it can
+// hold.
+//
+// A BitSet is either fixed-length (see NewBitSet), in which case any attempt
+// to use a bit at or beyond its length will panic, or growable (see
+// NewGrowableBitSet), in which case setting a bit beyond its length extends
+// it and the bits beyond its length are treated as clear. The zero value is
+// an empty, growable BitSet ready for use.
+//
+// A BitSet is not safe for concurrent use if any goroutine is changing it.
+type BitSet struct {
+	words []uint64
+	n     int
+	fixed bool
+}
+
+// wordsFor returns the number of words needed to hold n bits
+func wordsFor(n int) int {
+	return (n + width64 - 1) / width64
+}
+
+// checkBitSetLen panics if the length is negative
+func checkBitSetLen(n int) {
+	if n < 0 {
+		panic(fmt.Sprintf(
+			"Invalid BitSet length (%d), it must not be negative", n))
+	}
+}
+
+// NewBitSet returns a fixed-length BitSet holding n bits, all clear. It
+// will panic if n is negative.
+func NewBitSet(n int) *BitSet {
+	checkBitSetLen(n)
+
+	return &BitSet{
+		words: make([]uint64, wordsFor(n)),
+		n:     n,
+		fixed: true,
+	}
+}
+
+// NewGrowableBitSet returns a growable BitSet with an initial length of n
+// bits, all clear. It will panic if n is negative.
+func NewGrowableBitSet(n int) *BitSet {
+	checkBitSetLen(n)
+
+	return &BitSet{
+		words: make([]uint64, wordsFor(n)),
+		n:     n,
+	}
+}
+
+// Len returns the number of bits in the BitSet
+func (b *BitSet) Len() int {
+	return b.n
+}
+
+// Fixed returns true if the BitSet has a fixed length
+func (b *BitSet) Fixed() bool {
+	return b.fixed
+}
+
+// checkIndex panics if the index is negative or, for a fixed-length BitSet,
+// is not less than the length
+func (b *BitSet) checkIndex(i int) {
+	if i < 0 {
+		panic(fmt.Sprintf("Invalid BitSet index (%d), it must not be negative",
+			i))
+	}
+
+	if b.fixed && i >= b.n {
+		panic(fmt.Sprintf(
+			"Invalid BitSet index (%d), it must be less than %d", i, b.n))
+	}
+}
+
+// grow extends the BitSet so that it holds at least n bits
+func (b *BitSet) grow(n int) {
+	if n <= b.n {
+		return
+	}
+
+	oldWords, newWords := len(b.words), wordsFor(n)
+	if newWords > oldWords {
+		b.words = slices.Grow(b.words, newWords-oldWords)[:newWords]
+		clear(b.words[oldWords:])
+	}
+
+	b.n = n
+}
+
+// trim clears any bits in the last word beyond the length of the BitSet
+func (b *BitSet) trim() {
+	if extra := b.n % width64; extra != 0 {
+		b.words[len(b.words)-1] &= 1<<extra - 1
+	}
+}
+
+// Set sets bit i; a growable BitSet is extended if necessary. It will panic
+// if i is negative or, for a fixed-length BitSet, not less than the length.
+func (b *BitSet) Set(i int) {
+	b.checkIndex(i)
+	b.grow(i + 1)
+
+	b.words[i/width64] |= 1 << (i % width64)
+}
+
+// Clear clears bit i. It will panic if i is negative or, for a fixed-length
+// BitSet, not less than the length.
+func (b *BitSet) Clear(i int) {
+	b.checkIndex(i)
+
+	if i < b.n {
+		b.words[i/width64] &^= 1 << (i % width64)
+	}
+}
+
+// Flip inverts bit i; a growable BitSet is extended if necessary. It will
+// panic if i is negative or, for a fixed-length BitSet, not less than the
+// length.
+func (b *BitSet) Flip(i int) {
+	b.checkIndex(i)
+	b.grow(i + 1)
+
+	b.words[i/width64] ^= 1 << (i % width64)
+}
+
+// Test returns true if bit i is set. It will panic if i is negative or, for
+// a fixed-length BitSet, not less than the length.
+func (b *BitSet) Test(i int) bool {
+	b.checkIndex(i)
+
+	if i >= b.n {
+		return false
+	}
+
+	return b.words[i/width64]&(1<<(i%width64)) != 0
+}
+
+// Reset clears all the bits, leaving the length unchanged
+func (b *BitSet) Reset() {
+	clear(b.words)
+}
+
+// Count returns the number of bits that are set
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+
+	return count
+}
+
+// Clone returns a copy of the BitSet
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{
+		words: slices.Clone(b.words),
+		n:     b.n,
+		fixed: b.fixed,
+	}
+}
+
+// Equal returns true if the two BitSets have the same bits set, regardless
+// of their lengths.
+func (b *BitSet) Equal(o *BitSet) bool {
+	short, long := b.words, o.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+
+	for i, w := range short {
+		if w != long[i] {
+			return false
+		}
+	}
+
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fit makes sure that the BitSet can hold all the bits set in o; a growable
+// BitSet is extended if necessary. For a fixed-length BitSet it will panic
+// if o has a bit set at or beyond the length.
+func (b *BitSet) fit(o *BitSet) {
+	if o.n <= b.n {
+		return
+	}
+
+	if !b.fixed {
+		b.grow(o.n)
+		return
+	}
+
+	if i, ok := o.NextSet(b.n); ok {
+		panic(fmt.Sprintf(
+			"The other BitSet has bit %d set, beyond the fixed length (%d)",
+			i, b.n))
+	}
+}
+
+// Union sets every bit of b which is set in o. For a fixed-length BitSet
+// it will panic if o has a bit set at or beyond the length.
+func (b *BitSet) Union(o *BitSet) {
+	b.fit(o)
+
+	for i := range min(len(b.words), len(o.words)) {
+		b.words[i] |= o.words[i]
+	}
+}
+
+// Intersection clears every bit of b which is not set in o.
+func (b *BitSet) Intersection(o *BitSet) {
+	for i := range b.words {
+		if i < len(o.words) {
+			b.words[i] &= o.words[i]
+		} else {
+			b.words[i] = 0
+		}
+	}
+}
+
+// Difference clears every bit of b which is set in o.
+func (b *BitSet) Difference(o *BitSet) {
+	for i := range min(len(b.words), len(o.words)) {
+		b.words[i] &^= o.words[i]
+	}
+}
+
+// SymmetricDifference inverts every bit of b which is set in o, leaving
+// set just those bits set in one or other of the BitSets but not both. For
+// a fixed-length BitSet it will panic if o has a bit set at or beyond the
+// length.
+func (b *BitSet) SymmetricDifference(o *BitSet) {
+	b.fit(o)
+
+	for i := range min(len(b.words), len(o.words)) {
+		b.words[i] ^= o.words[i]
+	}
+}
+
+// All returns an iterator over the indexes of the bits that are set, in
+// increasing order.
+func (b *BitSet) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, w := range b.words {
+			for ; w != 0; w &= w - 1 {
+				if !yield(i*width64 + bits.TrailingZeros64(w)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// NextSet returns the index of the first bit at or after from which is set
+// and true. If there is no such bit it returns -1 and false. It will panic
+// if from is negative.
+func (b *BitSet) NextSet(from int) (int, bool) {
+	if from < 0 {
+		panic(fmt.Sprintf("Invalid BitSet index (%d), it must not be negative",
+			from))
+	}
+
+	if from >= b.n {
+		return -1, false
+	}
+
+	wi := from / width64
+	w := b.words[wi] >> (from % width64) << (from % width64)
+
+	for {
+		if w != 0 {
+			return wi*width64 + bits.TrailingZeros64(w), true
+		}
+
+		wi++
+		if wi == len(b.words) {
+			return -1, false
+		}
+
+		w = b.words[wi]
+	}
+}
+
+// NextClear returns the index of the first bit at or after from which is
+// clear and true. For a growable BitSet the bits beyond the length are
+// clear and so there is always such a bit. For a fixed-length BitSet, if
+// there is no such bit it returns -1 and false. It will panic if from is
+// negative.
+func (b *BitSet) NextClear(from int) (int, bool) {
+	if from < 0 {
+		panic(fmt.Sprintf("Invalid BitSet index (%d), it must not be negative",
+			from))
+	}
+
+	for wi := from / width64; wi < len(b.words); wi++ {
+		w := ^b.words[wi]
+		if wi == from/width64 {
+			w = w >> (from % width64) << (from % width64)
+		}
+
+		if w != 0 {
+			if i := wi*width64 + bits.TrailingZeros64(w); i < b.n {
+				return i, true
+			}
+
+			break
+		}
+	}
+
+	if b.fixed {
+		return -1, false
+	}
+
+	return max(from, b.n), true
+}
+
+// ShiftUp moves every bit up by k places, so that bit i becomes bit i+k,
+// and clears the lowest k bits. A growable BitSet is extended by k bits; a
+// fixed-length BitSet loses any bits moved beyond its length. It will panic
+// if k is negative.
+func (b *BitSet) ShiftUp(k int) {
+	if k < 0 {
+		panic(fmt.Sprintf("Invalid shift (%d), it must not be negative", k))
+	}
+
+	if !b.fixed {
+		b.grow(b.n + k)
+	}
+
+	ws, bs := k/width64, uint(k%width64)
+
+	for i := len(b.words) - 1; i >= 0; i-- {
+		var w uint64
+
+		if src := i - ws; src >= 0 {
+			w = b.words[src] << bs
+			if bs > 0 && src > 0 {
+				w |= b.words[src-1] >> (width64 - bs)
+			}
+		}
+
+		b.words[i] = w
+	}
+
+	b.trim()
+}
+
+// ShiftDown moves every bit down by k places, so that bit i becomes bit
+// i-k; the lowest k bits are lost. The length is unchanged. It will panic if
+// k is negative.
+func (b *BitSet) ShiftDown(k int) {
+	if k < 0 {
+		panic(fmt.Sprintf("Invalid shift (%d), it must not be negative", k))
+	}
+
+	ws, bs := k/width64, uint(k%width64)
+
+	for i := range b.words {
+		var w uint64
+
+		if src := i + ws; src < len(b.words) {
+			w = b.words[src] >> bs
+			if bs > 0 && src+1 < len(b.words) {
+				w |= b.words[src+1] << (width64 - bs)
+			}
+		}
+
+		b.words[i] = w
+	}
+}
+
+// String returns the BitSet as a string of '0' and '1' characters, one for
+// each bit, starting with bit zero.
+func (b *BitSet) String() string {
+	var sb strings.Builder
+
+	sb.Grow(b.n)
+
+	for i := range b.n {
+		if b.words[i/width64]&(1<<(i%width64)) != 0 {
+			sb.WriteByte('1')
+		} else {
+			sb.WriteByte('0')
+		}
+	}
+
+	return sb.String()
+}
+
+// MarshalText encodes the BitSet in the same form as String.
+func (b *BitSet) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText decodes the text form of a BitSet, as generated by
+// MarshalText, replacing the contents and the length of b. Whether b is
+// fixed-length or growable is unchanged.
+func (b *BitSet) UnmarshalText(text []byte) error {
+	words := make([]uint64, wordsFor(len(text)))
+
+	for i, c := range text {
+		switch c {
+		case '1':
+			words[i/width64] |= 1 << (i % width64)
+		case '0':
+		default:
+			return fmt.Errorf("invalid character (%q) at %d in the BitSet text",
+				c, i)
+		}
+	}
+
+	b.words, b.n = words, len(text)
+
+	return nil
+}
+
+// MarshalBinary encodes the BitSet, including its length and whether it is
+// fixed-length, into a binary form. This can be decoded by UnmarshalBinary.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	var flags byte
+	if b.fixed {
+		flags |= bitSetFixedFlag
+	}
+
+	data := make([]byte, 0, 2+binary.MaxVarintLen64+len(b.words)*wordBytes)
+	data = append(data, bitSetVersion, flags)
+	data = binary.AppendUvarint(data, uint64(b.n)) //nolint:gosec
+
+	for _, w := range b.words {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes the binary form of a BitSet, as generated by
+// MarshalBinary, replacing the contents of b.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	const headerLen = 2
+
+	if len(data) < headerLen {
+		return errBitSetTruncated
+	}
+
+	if data[0] != bitSetVersion {
+		return errBitSetBadVersion
+	}
+
+	flags := data[1]
+	if flags&^bitSetFixedFlag != 0 {
+		return errBitSetBadEncode
+	}
+
+	n, vLen := binary.Uvarint(data[headerLen:])
+	if vLen <= 0 {
+		return errBitSetTruncated
+	}
+
+	data = data[headerLen+vLen:]
+
+	if n > uint64(len(data))*bitsInByte {
+		return errBitSetTruncated
+	}
+
+	nWords := wordsFor(int(n)) //nolint:gosec
+	if len(data) != nWords*wordBytes {
+		return errBitSetBadEncode
+	}
+
+	bs := &BitSet{
+		words: make([]uint64, nWords),
+		n:     int(n), //nolint:gosec
+		fixed: flags&bitSetFixedFlag != 0,
+	}
+
+	for i := range bs.words {
+		bs.words[i] = binary.LittleEndian.Uint64(data[i*wordBytes:])
+	}
+
+	if extra := bs.n % width64; extra != 0 && bs.words[nWords-1]>>extra != 0 {
+		return errBitSetBadEncode
+	}
+
+	*b = *bs
+
+	return nil
+}
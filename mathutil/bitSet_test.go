@@ -0,0 +1,379 @@
+package mathutil_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkBitSet returns a growable BitSet with the given bits set
+func mkBitSet(idx ...int) *mathutil.BitSet {
+	b := &mathutil.BitSet{}
+	for _, i := range idx {
+		b.Set(i)
+	}
+
+	return b
+}
+
+func TestBitSetBasics(t *testing.T) {
+	b := mathutil.NewBitSet(130)
+	testhelper.DiffInt(t, "NewBitSet", "len", b.Len(), 130)
+	testhelper.DiffBool(t, "NewBitSet", "fixed", b.Fixed(), true)
+
+	for _, i := range []int{0, 63, 64, 129} {
+		b.Set(i)
+	}
+
+	testhelper.DiffInt(t, "Set", "count", b.Count(), 4)
+	testhelper.DiffBool(t, "Test", "bit 64", b.Test(64), true)
+	testhelper.DiffBool(t, "Test", "bit 65", b.Test(65), false)
+
+	b.Clear(64)
+	b.Flip(65)
+	b.Flip(0)
+	testhelper.DiffSlice(t, "All", "after clear and flip",
+		slices.Collect(b.All()), []int{63, 65, 129})
+
+	c := b.Clone()
+	b.Reset()
+	testhelper.DiffInt(t, "Reset", "count", b.Count(), 0)
+	testhelper.DiffInt(t, "Clone", "count", c.Count(), 3)
+
+	var g mathutil.BitSet
+
+	testhelper.DiffBool(t, "zero BitSet", "test", g.Test(1000), false)
+	g.Clear(1000)
+	testhelper.DiffInt(t, "zero BitSet", "len after clear", g.Len(), 0)
+	g.Set(1000)
+	testhelper.DiffInt(t, "zero BitSet", "len after set", g.Len(), 1001)
+	testhelper.DiffBool(t, "Equal", "different lengths",
+		g.Equal(mkBitSet(1000)), true)
+	testhelper.DiffBool(t, "Equal", "different bits",
+		g.Equal(mkBitSet(999)), false)
+}
+
+func TestBitSetPanics(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		f func()
+	}{
+		{
+			ID: testhelper.MkID("fixed, too big"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid BitSet index (10), it must be less than 10"),
+			f: func() { mathutil.NewBitSet(10).Set(10) },
+		},
+		{
+			ID: testhelper.MkID("negative index"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid BitSet index (-1), it must not be negative"),
+			f: func() { mkBitSet().Test(-1) },
+		},
+		{
+			ID: testhelper.MkID("negative length"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid BitSet length (-1), it must not be negative"),
+			f: func() { mathutil.NewGrowableBitSet(-1) },
+		},
+		{
+			ID: testhelper.MkID("union beyond the fixed length"),
+			ExpPanic: testhelper.MkExpPanic(
+				"The other BitSet has bit 12 set," +
+					" beyond the fixed length (10)"),
+			f: func() { mathutil.NewBitSet(10).Union(mkBitSet(1, 12)) },
+		},
+		{
+			ID:       testhelper.MkID("union, longer but within length"),
+			ExpPanic: testhelper.ExpPanic{},
+			f: func() {
+				o := mathutil.NewGrowableBitSet(100)
+				o.Set(9)
+				mathutil.NewBitSet(10).Union(o)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(tc.f)
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+func TestBitSetOps(t *testing.T) {
+	a := []int{1, 2, 64, 200}
+	b := []int{2, 3, 64, 65}
+
+	testCases := []struct {
+		testhelper.ID
+		op          func(x, y *mathutil.BitSet)
+		commutative bool
+		exp         []int
+	}{
+		{
+			ID:          testhelper.MkID("union"),
+			commutative: true,
+			op:          (*mathutil.BitSet).Union,
+			exp:         []int{1, 2, 3, 64, 65, 200},
+		},
+		{
+			ID:          testhelper.MkID("intersection"),
+			commutative: true,
+			op:          (*mathutil.BitSet).Intersection,
+			exp:         []int{2, 64},
+		},
+		{
+			ID:  testhelper.MkID("difference"),
+			op:  (*mathutil.BitSet).Difference,
+			exp: []int{1, 200},
+		},
+		{
+			ID:          testhelper.MkID("symmetric difference"),
+			commutative: true,
+			op:          (*mathutil.BitSet).SymmetricDifference,
+			exp:         []int{1, 3, 65, 200},
+		},
+	}
+
+	for _, tc := range testCases {
+		x := mkBitSet(a...)
+		tc.op(x, mkBitSet(b...))
+		testhelper.DiffSlice(t, tc.IDStr(), "a op b",
+			slices.Collect(x.All()), tc.exp)
+
+		if !tc.commutative {
+			continue
+		}
+
+		y := mkBitSet(b...)
+		tc.op(y, mkBitSet(a...))
+		testhelper.DiffSlice(t, tc.IDStr(), "b op a",
+			slices.Collect(y.All()), tc.exp)
+	}
+}
+
+func TestBitSetNext(t *testing.T) {
+	b := mathutil.NewBitSet(130)
+	for i := range 130 {
+		if i != 70 && i != 129 {
+			b.Set(i)
+		}
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		from         int
+		expSet       int
+		expSetOK     bool
+		expClear     int
+		expClearOK   bool
+		expGrowClear int
+	}{
+		{
+			ID:           testhelper.MkID("start"),
+			from:         0,
+			expSet:       0,
+			expSetOK:     true,
+			expClear:     70,
+			expClearOK:   true,
+			expGrowClear: 70,
+		},
+		{
+			ID:           testhelper.MkID("at the gap"),
+			from:         70,
+			expSet:       71,
+			expSetOK:     true,
+			expClear:     70,
+			expClearOK:   true,
+			expGrowClear: 70,
+		},
+		{
+			ID:           testhelper.MkID("last bit"),
+			from:         129,
+			expSet:       -1,
+			expSetOK:     false,
+			expClear:     129,
+			expClearOK:   true,
+			expGrowClear: 129,
+		},
+		{
+			ID:           testhelper.MkID("beyond the end"),
+			from:         500,
+			expSet:       -1,
+			expSetOK:     false,
+			expClear:     -1,
+			expClearOK:   false,
+			expGrowClear: 500,
+		},
+	}
+
+	g := mathutil.NewGrowableBitSet(0)
+	for i := range 129 {
+		if i != 70 {
+			g.Set(i)
+		}
+	}
+
+	for _, tc := range testCases {
+		i, ok := b.NextSet(tc.from)
+		testhelper.DiffInt(t, tc.IDStr(), "NextSet", i, tc.expSet)
+		testhelper.DiffBool(t, tc.IDStr(), "NextSet ok", ok, tc.expSetOK)
+
+		i, ok = b.NextClear(tc.from)
+		testhelper.DiffInt(t, tc.IDStr(), "NextClear", i, tc.expClear)
+		testhelper.DiffBool(t, tc.IDStr(), "NextClear ok", ok, tc.expClearOK)
+
+		i, ok = g.NextClear(tc.from)
+		testhelper.DiffInt(t, tc.IDStr(), "NextClear (growable)",
+			i, tc.expGrowClear)
+		testhelper.DiffBool(t, tc.IDStr(), "NextClear ok (growable)",
+			ok, true)
+	}
+
+	full := mathutil.NewBitSet(64)
+	for i := range 64 {
+		full.Set(i)
+	}
+
+	_, ok := full.NextClear(0)
+	testhelper.DiffBool(t, "NextClear", "full", ok, false)
+
+	g.Set(129)
+	i, _ := g.NextClear(71)
+	testhelper.DiffInt(t, "NextClear", "growable, all set", i, 130)
+}
+
+func TestBitSetShift(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		k            int
+		expUp        []int
+		expUpFixed   []int
+		expDownFixed []int
+	}{
+		{
+			ID:           testhelper.MkID("no shift"),
+			k:            0,
+			expUp:        []int{0, 63, 99},
+			expUpFixed:   []int{0, 63, 99},
+			expDownFixed: []int{0, 63, 99},
+		},
+		{
+			ID:           testhelper.MkID("by 1"),
+			k:            1,
+			expUp:        []int{1, 64, 100},
+			expUpFixed:   []int{1, 64},
+			expDownFixed: []int{62, 98},
+		},
+		{
+			ID:           testhelper.MkID("by 64"),
+			k:            64,
+			expUp:        []int{64, 127, 163},
+			expUpFixed:   []int{64},
+			expDownFixed: []int{35},
+		},
+		{
+			ID:           testhelper.MkID("by 70"),
+			k:            70,
+			expUp:        []int{70, 133, 169},
+			expUpFixed:   []int{70},
+			expDownFixed: []int{29},
+		},
+		{
+			ID:           testhelper.MkID("beyond the length"),
+			k:            500,
+			expUp:        []int{500, 563, 599},
+			expUpFixed:   []int{},
+			expDownFixed: []int{},
+		},
+	}
+
+	bitsSet := func(b *mathutil.BitSet) []int {
+		return append([]int{}, slices.Collect(b.All())...)
+	}
+
+	for _, tc := range testCases {
+		g := mkBitSet(0, 63, 99)
+		g.ShiftUp(tc.k)
+		testhelper.DiffSlice(t, tc.IDStr(), "ShiftUp", bitsSet(g), tc.expUp)
+		testhelper.DiffInt(t, tc.IDStr(), "ShiftUp len", g.Len(), 100+tc.k)
+
+		g.ShiftDown(tc.k)
+		testhelper.DiffSlice(t, tc.IDStr(), "ShiftDown after ShiftUp",
+			bitsSet(g), []int{0, 63, 99})
+
+		f := mathutil.NewBitSet(100)
+		for _, i := range []int{0, 63, 99} {
+			f.Set(i)
+		}
+
+		c := f.Clone()
+
+		f.ShiftUp(tc.k)
+		testhelper.DiffSlice(t, tc.IDStr(), "ShiftUp (fixed)",
+			bitsSet(f), tc.expUpFixed)
+
+		c.ShiftDown(tc.k)
+		testhelper.DiffSlice(t, tc.IDStr(), "ShiftDown (fixed)",
+			bitsSet(c), tc.expDownFixed)
+	}
+}
+
+func TestBitSetMarshal(t *testing.T) {
+	b := mathutil.NewBitSet(70)
+	for _, i := range []int{0, 2, 64, 69} {
+		b.Set(i)
+	}
+
+	text, err := b.MarshalText()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expText := "101" + strings.Repeat("0", 61) + "100001"
+	testhelper.DiffString(t, "MarshalText", "text", string(text), expText)
+
+	var fromText mathutil.BitSet
+	if err = fromText.UnmarshalText(text); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffBool(t, "UnmarshalText", "equal", fromText.Equal(b), true)
+	testhelper.DiffInt(t, "UnmarshalText", "len", fromText.Len(), 70)
+
+	if err = fromText.UnmarshalText([]byte("012")); err == nil {
+		t.Error("UnmarshalText: bad character: an error was expected")
+	}
+
+	data, err := b.MarshalBinary()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var fromBin mathutil.BitSet
+	if err = fromBin.UnmarshalBinary(data); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffBool(t, "UnmarshalBinary", "equal", fromBin.Equal(b), true)
+	testhelper.DiffInt(t, "UnmarshalBinary", "len", fromBin.Len(), 70)
+	testhelper.DiffBool(t, "UnmarshalBinary", "fixed", fromBin.Fixed(), true)
+
+	badData := [][]byte{
+		nil,
+		{2, 0, 0},
+		{1, 2, 0},
+		data[:len(data)-1],
+		append(slices.Clone(data), 0),
+		append(slices.Clone(data[:len(data)-1]), 0x80),
+	}
+	for i, bd := range badData {
+		if err = fromBin.UnmarshalBinary(bd); err == nil {
+			t.Errorf("UnmarshalBinary: bad data %d: an error was expected", i)
+		}
+	}
+}
@@ -1,10 +1,29 @@
 package mathutil
 
-import "reflect"
+import (
+	"math"
+	"reflect"
+	"unsafe"
+)
 
-const bitsInByte = 8
+const (
+	bitsInByte  = 8
+	float32Bits = 32
+	float64Bits = 64
+)
 
-// BitsInType returns the number of bits needed to store this type
+// Mantissa and exponent bits of the IEEE 754 float types
+const (
+	float32MantissaBits = 23
+	float32ExponentBits = 8
+	float64MantissaBits = 52
+	float64ExponentBits = 11
+)
+
+// BitsInType returns the number of bits needed to store this type. Note
+// that this uses reflection and gives the size of any type, including
+// those, such as strings, slices and structs, for which the number of bits
+// has little meaning. For numeric types BitsOf is to be preferred.
 func BitsInType(v any) int {
 	vt := reflect.TypeOf(v)
 	if vt == nil {
@@ -13,3 +32,85 @@ func BitsInType(v any) int {
 
 	return int(vt.Size() * bitsInByte) //nolint:gosec
 }
+
+// BitsOf returns the number of bits in the numeric type T. It needs no
+// value and uses no reflection.
+func BitsOf[T Number]() int {
+	var v T
+
+	return int(unsafe.Sizeof(v)) * bitsInByte
+}
+
+// isFloat returns true if T is a floating point type
+func isFloat[T Number]() bool {
+	one := T(1)
+
+	return one/2 != 0
+}
+
+// NumberInfo records the properties of a numeric type
+type NumberInfo[T Number] struct {
+	// Bits is the number of bits in the type
+	Bits int
+	// Signed is true if the type can hold negative values
+	Signed bool
+	// Float is true for the floating point types
+	Float bool
+	// Min and Max are the smallest and largest finite values of the type
+	Min, Max T
+	// MantissaBits is the number of bits stored for the fraction of a
+	// floating point value; the precision is one more than this because
+	// of the implicit leading bit. It is zero for the integer types.
+	MantissaBits int
+	// ExponentBits is the number of bits in the exponent of a floating
+	// point value. It is zero for the integer types.
+	ExponentBits int
+	// MaxExactInt is the largest integer such that it and every smaller
+	// non-negative integer can be held exactly in the type. For an
+	// integer type it is the same as Max.
+	MaxExactInt uint64
+}
+
+// InfoOf returns the properties of the numeric type T. Like BitsOf it
+// needs no value and uses no reflection. It can be used, for instance, to
+// check whether every value of one type can be held exactly in another.
+func InfoOf[T Number]() NumberInfo[T] {
+	ni := NumberInfo[T]{
+		Bits:  BitsOf[T](),
+		Float: isFloat[T](),
+	}
+
+	if ni.Float {
+		ni.Signed = true
+
+		maxVal := math.MaxFloat64
+		ni.MantissaBits = float64MantissaBits
+		ni.ExponentBits = float64ExponentBits
+
+		if ni.Bits == float32Bits {
+			maxVal = math.MaxFloat32
+			ni.MantissaBits = float32MantissaBits
+			ni.ExponentBits = float32ExponentBits
+		}
+
+		ni.Max = T(maxVal)
+		ni.Min = -ni.Max
+		ni.MaxExactInt = uint64(1) << (ni.MantissaBits + 1)
+
+		return ni
+	}
+
+	var zero T
+
+	ni.Signed = zero-1 < 0
+
+	ni.MaxExactInt = ^uint64(0) >> (float64Bits - ni.Bits)
+	if ni.Signed {
+		ni.MaxExactInt >>= 1
+		ni.Min = -T(ni.MaxExactInt) - 1 //nolint:gosec
+	}
+
+	ni.Max = T(ni.MaxExactInt)
+
+	return ni
+}
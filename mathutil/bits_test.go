@@ -1,6 +1,7 @@
 package mathutil_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/nickwells/mathutil.mod/v2/mathutil"
@@ -60,3 +61,61 @@ func TestBitsInType(t *testing.T) {
 		testhelper.DiffInt(t, tc.IDStr(), "bits", b, tc.expBits)
 	}
 }
+
+func TestBitsOf(t *testing.T) {
+	testhelper.DiffInt(t, "BitsOf", "int8", mathutil.BitsOf[int8](), 8)
+	testhelper.DiffInt(t, "BitsOf", "uint16", mathutil.BitsOf[uint16](), 16)
+	testhelper.DiffInt(t, "BitsOf", "float32", mathutil.BitsOf[float32](), 32)
+	testhelper.DiffInt(t, "BitsOf", "float64", mathutil.BitsOf[float64](), 64)
+	testhelper.DiffInt(t, "BitsOf", "int32", mathutil.BitsOf[int32](), 32)
+	testhelper.DiffInt(t, "BitsOf", "uint64", mathutil.BitsOf[uint64](), 64)
+	testhelper.DiffInt(t, "BitsOf", "int",
+		mathutil.BitsOf[int](), mathutil.BitsInType(0))
+	testhelper.DiffInt(t, "BitsOf", "uintptr",
+		mathutil.BitsOf[uintptr](), mathutil.BitsInType(uintptr(0)))
+}
+
+func TestInfoOf(t *testing.T) {
+	i8 := mathutil.InfoOf[int8]()
+	testhelper.DiffInt(t, "int8", "bits", i8.Bits, 8)
+	testhelper.DiffBool(t, "int8", "signed", i8.Signed, true)
+	testhelper.DiffBool(t, "int8", "float", i8.Float, false)
+	testhelper.DiffInt(t, "int8", "min", i8.Min, math.MinInt8)
+	testhelper.DiffInt(t, "int8", "max", i8.Max, math.MaxInt8)
+	testhelper.DiffInt(t, "int8", "max exact", i8.MaxExactInt, math.MaxInt8)
+	testhelper.DiffInt(t, "int8", "mantissa bits", i8.MantissaBits, 0)
+
+	i64 := mathutil.InfoOf[int64]()
+	testhelper.DiffInt(t, "int64", "min", i64.Min, math.MinInt64)
+	testhelper.DiffInt(t, "int64", "max", i64.Max, math.MaxInt64)
+
+	u64 := mathutil.InfoOf[uint64]()
+	testhelper.DiffBool(t, "uint64", "signed", u64.Signed, false)
+	testhelper.DiffInt(t, "uint64", "min", u64.Min, 0)
+	testhelper.DiffInt(t, "uint64", "max", u64.Max, math.MaxUint64)
+	testhelper.DiffInt(t, "uint64", "max exact",
+		u64.MaxExactInt, math.MaxUint64)
+
+	u16 := mathutil.InfoOf[uint16]()
+	testhelper.DiffInt(t, "uint16", "max", u16.Max, math.MaxUint16)
+
+	f32 := mathutil.InfoOf[float32]()
+	testhelper.DiffInt(t, "float32", "bits", f32.Bits, 32)
+	testhelper.DiffBool(t, "float32", "signed", f32.Signed, true)
+	testhelper.DiffBool(t, "float32", "float", f32.Float, true)
+	testhelper.DiffFloat(t, "float32", "max", f32.Max, math.MaxFloat32, 0)
+	testhelper.DiffFloat(t, "float32", "min", f32.Min, -math.MaxFloat32, 0)
+	testhelper.DiffInt(t, "float32", "mantissa bits", f32.MantissaBits, 23)
+	testhelper.DiffInt(t, "float32", "exponent bits", f32.ExponentBits, 8)
+	testhelper.DiffInt(t, "float32", "max exact", f32.MaxExactInt, 1<<24)
+
+	f64 := mathutil.InfoOf[float64]()
+	testhelper.DiffFloat(t, "float64", "max", f64.Max, math.MaxFloat64, 0)
+	testhelper.DiffInt(t, "float64", "mantissa bits", f64.MantissaBits, 52)
+	testhelper.DiffInt(t, "float64", "exponent bits", f64.ExponentBits, 11)
+	testhelper.DiffInt(t, "float64", "max exact", f64.MaxExactInt, 1<<53)
+
+	testhelper.DiffBool(t, "float32", "max exact is exact",
+		float32(f32.MaxExactInt) == 1<<24 &&
+			float32(f32.MaxExactInt+1) == float32(f32.MaxExactInt), true)
+}
@@ -0,0 +1,459 @@
+package mathutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+const (
+	checkValX     = 10 // the value of an 'X' check character
+	mod11         = 11
+	mod97         = 97
+	ibanHeaderLen = 4
+)
+
+var errCheckNegative = errors.New("the value must not be negative")
+
+// verhoeffD is the multiplication table of the dihedral group D5
+var verhoeffD = [10][10]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+// verhoeffP is the Verhoeff permutation table, applied according to the
+// position of the digit
+var verhoeffP = [8][10]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+// verhoeffInv gives the inverse of each element of D5
+var verhoeffInv = [10]uint8{0, 4, 3, 2, 1, 5, 6, 7, 8, 9}
+
+// dammTable is a totally anti-symmetric quasigroup of order 10
+var dammTable = [10][10]uint8{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// CheckDigitScheme selects the algorithm used to calculate check digits.
+type CheckDigitScheme int
+
+// These are the available check digit schemes.
+const (
+	// CheckLuhn is the Luhn (mod 10) algorithm used for payment card
+	// numbers
+	CheckLuhn CheckDigitScheme = iota
+	// CheckVerhoeff is Verhoeff's algorithm which detects all single digit
+	// errors and all transpositions of adjacent digits
+	CheckVerhoeff
+	// CheckDamm is Damm's algorithm which, like Verhoeff's, detects all
+	// single digit errors and adjacent transpositions
+	CheckDamm
+	// CheckISO7064Mod11_2 is the ISO 7064 MOD 11-2 scheme used by ISNI
+	// and ORCID. The check character may be 'X'.
+	CheckISO7064Mod11_2
+	// CheckISO7064Mod97_10 is the ISO 7064 MOD 97-10 scheme which gives
+	// two check digits. Letters are allowed and are given the values 10 to
+	// 35, as for an IBAN.
+	CheckISO7064Mod97_10
+	// CheckISBN10 is the scheme for the 10 digit ISBN. The check character
+	// may be 'X'.
+	CheckISBN10
+	// CheckISBN13 is the scheme for the 13 digit ISBN; it is the same as
+	// CheckEAN but the ISBN must start with 978 or 979.
+	CheckISBN13
+	// CheckEAN is the scheme for EAN-8, EAN-13, UPC-A and the other GTIN
+	// codes.
+	CheckEAN
+)
+
+// String returns a string value for the CheckDigitScheme
+func (cs CheckDigitScheme) String() string {
+	switch cs {
+	case CheckLuhn:
+		return "CheckLuhn"
+	case CheckVerhoeff:
+		return "CheckVerhoeff"
+	case CheckDamm:
+		return "CheckDamm"
+	case CheckISO7064Mod11_2:
+		return "CheckISO7064Mod11_2"
+	case CheckISO7064Mod97_10:
+		return "CheckISO7064Mod97_10"
+	case CheckISBN10:
+		return "CheckISBN10"
+	case CheckISBN13:
+		return "CheckISBN13"
+	case CheckEAN:
+		return "CheckEAN"
+	}
+
+	return fmt.Sprintf("CheckDigitScheme(%d)", int(cs))
+}
+
+// checkLen returns the number of check characters the scheme gives
+func (cs CheckDigitScheme) checkLen() int {
+	if cs == CheckISO7064Mod97_10 {
+		return 2 //nolint:mnd
+	}
+
+	return 1
+}
+
+// payloadLen returns the number of digits the scheme requires before the
+// check characters, or zero if any number is allowed
+func (cs CheckDigitScheme) payloadLen() int {
+	switch cs {
+	case CheckISBN10:
+		return 9 //nolint:mnd
+	case CheckISBN13:
+		return 12 //nolint:mnd
+	}
+
+	return 0
+}
+
+// luhn returns the Luhn check digit for the payload
+func luhn(payload []uint8) uint8 {
+	sum := 0
+
+	for i, d := range payload {
+		v := int(d)
+		if (len(payload)-i)%2 == 1 { // every other digit from the right
+			v *= 2
+			if v > 9 { //nolint:mnd
+				v -= 9
+			}
+		}
+
+		sum += v
+	}
+
+	return uint8((base10 - sum%base10) % base10)
+}
+
+// verhoeff returns the Verhoeff check digit for the payload
+func verhoeff(payload []uint8) uint8 {
+	c := uint8(0)
+
+	for i := range payload {
+		d := payload[len(payload)-1-i]
+		c = verhoeffD[c][verhoeffP[(i+1)%len(verhoeffP)][d]]
+	}
+
+	return verhoeffInv[c]
+}
+
+// damm returns the Damm check digit for the payload
+func damm(payload []uint8) uint8 {
+	interim := uint8(0)
+	for _, d := range payload {
+		interim = dammTable[interim][d]
+	}
+
+	return interim
+}
+
+// mod11_2 returns the ISO 7064 MOD 11-2 check value for the payload
+func mod11_2(payload []uint8) uint8 {
+	p := 0
+	for _, d := range payload {
+		p = ((p + int(d)) * 2) % mod11 //nolint:mnd
+	}
+
+	return uint8((mod11 + 1 - p) % mod11)
+}
+
+// mod97Rem returns the remainder of the value, with letters expanded to two
+// digits, when divided by 97
+func mod97Rem(vals []uint8) int {
+	r := 0
+
+	for _, v := range vals {
+		if v >= base10 {
+			r = (r*base10*base10 + int(v)) % mod97
+		} else {
+			r = (r*base10 + int(v)) % mod97
+		}
+	}
+
+	return r
+}
+
+// mod97_10 returns the two ISO 7064 MOD 97-10 check digits for the payload
+func mod97_10(payload []uint8) []uint8 {
+	c := mod97 + 1 - (mod97Rem(payload)*base10*base10)%mod97
+
+	return []uint8{uint8(c / base10), uint8(c % base10)}
+}
+
+// isbn10 returns the ISBN-10 check value for the payload
+func isbn10(payload []uint8) uint8 {
+	sum := 0
+	for i, d := range payload {
+		sum += (base10 - i) * int(d)
+	}
+
+	return uint8((mod11 - sum%mod11) % mod11)
+}
+
+// ean returns the EAN check digit for the payload
+func ean(payload []uint8) uint8 {
+	const oddWeight = 3
+
+	sum := 0
+
+	for i, d := range payload {
+		if (len(payload)-i)%2 == 1 {
+			sum += oddWeight * int(d)
+		} else {
+			sum += int(d)
+		}
+	}
+
+	return uint8((base10 - sum%base10) % base10)
+}
+
+// compute returns the check values for the payload. It will panic if the
+// scheme is not known.
+func (cs CheckDigitScheme) compute(payload []uint8) []uint8 {
+	switch cs {
+	case CheckLuhn:
+		return []uint8{luhn(payload)}
+	case CheckVerhoeff:
+		return []uint8{verhoeff(payload)}
+	case CheckDamm:
+		return []uint8{damm(payload)}
+	case CheckISO7064Mod11_2:
+		return []uint8{mod11_2(payload)}
+	case CheckISO7064Mod97_10:
+		return mod97_10(payload)
+	case CheckISBN10:
+		return []uint8{isbn10(payload)}
+	case CheckISBN13, CheckEAN:
+		return []uint8{ean(payload)}
+	}
+
+	panic(fmt.Sprintf("Invalid check digit scheme: %s", cs))
+}
+
+// checkPayload returns a non-nil error if the payload is not valid for the
+// scheme
+func (cs CheckDigitScheme) checkPayload(payload []uint8) error {
+	if n := cs.payloadLen(); n != 0 && len(payload) != n {
+		return fmt.Errorf("%s needs %d digits before the check digit, not %d",
+			cs, n, len(payload))
+	}
+
+	if cs == CheckISBN13 {
+		if payload[0] != 9 || payload[1] != 7 || //nolint:mnd
+			(payload[2] != 8 && payload[2] != 9) { //nolint:mnd
+			return fmt.Errorf("%s must start with 978 or 979", cs)
+		}
+	}
+
+	return nil
+}
+
+// parseCheckChars returns the values of the characters in s. Hyphens and
+// spaces are ignored. Letters are only allowed for the MOD 97-10 scheme and
+// an 'X' only as the last of the check characters for schemes that use
+// it.
+func (cs CheckDigitScheme) parseCheckChars(s string, hasCheck bool) (
+	[]uint8, error,
+) {
+	s = strings.NewReplacer("-", "", " ", "").Replace(s)
+
+	vals := make([]uint8, 0, len(s))
+
+	for i := range len(s) {
+		c := s[i]
+
+		switch {
+		case c >= '0' && c <= '9':
+			vals = append(vals, c-'0')
+		case cs == CheckISO7064Mod97_10 &&
+			((c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')):
+			vals = append(vals, (c|0x20)-'a'+base10) //nolint:mnd
+		case (c == 'X' || c == 'x') && hasCheck && i == len(s)-1 &&
+			(cs == CheckISO7064Mod11_2 || cs == CheckISBN10):
+			vals = append(vals, checkValX)
+		default:
+			return nil, fmt.Errorf("invalid character (%q) for %s", c, cs)
+		}
+	}
+
+	return vals, nil
+}
+
+// checkChars returns the check characters for the check values
+func checkChars(vals []uint8) string {
+	var sb strings.Builder
+
+	for _, v := range vals {
+		if v == checkValX {
+			sb.WriteByte('X')
+		} else {
+			sb.WriteByte('0' + v)
+		}
+	}
+
+	return sb.String()
+}
+
+// CheckDigits returns the check characters for the payload using the given
+// scheme; these should be appended to the payload. Hyphens and spaces in
+// the payload are ignored. A non-nil error is returned if the payload has
+// any invalid characters or if it has the wrong length for the scheme (for
+// instance, an ISBN-10 payload must have 9 digits). It will panic if the
+// scheme is not known.
+func CheckDigits(payload string, cs CheckDigitScheme) (string, error) {
+	vals, err := cs.parseCheckChars(payload, false)
+	if err != nil {
+		return "", err
+	}
+
+	if err := cs.checkPayload(vals); err != nil {
+		return "", err
+	}
+
+	return checkChars(cs.compute(vals)), nil
+}
+
+// ValidCheckDigits returns true if the string ends with the correct check
+// characters for the rest of the string using the given scheme. Hyphens and
+// spaces are ignored. It returns false if there are any invalid characters
+// or if the string has the wrong length for the scheme. It will panic if the
+// scheme is not known.
+func ValidCheckDigits(s string, cs CheckDigitScheme) bool {
+	vals, err := cs.parseCheckChars(s, true)
+	if err != nil || len(vals) <= cs.checkLen() {
+		return false
+	}
+
+	split := len(vals) - cs.checkLen()
+
+	payload, check := vals[:split], vals[split:]
+	if cs.checkPayload(payload) != nil {
+		return false
+	}
+
+	return checkChars(cs.compute(payload)) == checkChars(check)
+}
+
+// intCheckVals returns the digits of the non-negative value v, padded with
+// leading zeros to n digits
+func intCheckVals[T constraints.Integer](v T, n int) []uint8 {
+	digits := DigitVals(v, base10)
+	if pad := n - len(digits); pad > 0 {
+		digits = append(make([]uint8, pad), digits...)
+	}
+
+	return digits
+}
+
+// CheckDigitsInt returns the value v with the check digits for the given
+// scheme appended. For schemes with a fixed length, such as ISBN-10, v is
+// treated as if it had leading zeros. A non-nil error is returned if v is
+// negative, if it has too many digits for the scheme, if the check
+// character is 'X' (which cannot be represented as an integer) or if the
+// result is out of range for the type. It will panic if the scheme is not
+// known.
+func CheckDigitsInt[T constraints.Integer](v T, cs CheckDigitScheme) (
+	T, error,
+) {
+	if v < 0 {
+		return 0, errCheckNegative
+	}
+
+	payload := intCheckVals(v, cs.payloadLen())
+	if err := cs.checkPayload(payload); err != nil {
+		return 0, err
+	}
+
+	check := cs.compute(payload)
+
+	mag := uint64(v)
+
+	for _, c := range check {
+		if c == checkValX {
+			return 0, fmt.Errorf(
+				"the %s check character is 'X' which is not a digit", cs)
+		}
+
+		var ok bool
+		if mag, ok = appendDigit(mag, uint64(c), base10); !ok {
+			return 0, errParseOutOfRange
+		}
+	}
+
+	return fromMagnitude[T](mag, false)
+}
+
+// ValidCheckDigitsInt returns true if the last digits of v are the correct
+// check digits for the preceding digits using the given scheme. For
+// schemes with a fixed length, such as ISBN-10, v is treated as if it had
+// leading zeros. It returns false if v is negative. It will panic if the
+// scheme is not known.
+func ValidCheckDigitsInt[T constraints.Integer](v T, cs CheckDigitScheme) bool {
+	if v < 0 {
+		return false
+	}
+
+	n := 0
+	if pl := cs.payloadLen(); pl != 0 {
+		n = pl + cs.checkLen()
+	}
+
+	return ValidCheckDigits(checkChars(intCheckVals(v, n)), cs)
+}
+
+// ValidIBAN returns true if s is a valid International Bank Account Number
+// according to its check digits; the country-specific format is not
+// checked. Spaces are ignored and letters may be in either case.
+func ValidIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) <= ibanHeaderLen {
+		return false
+	}
+
+	for i := range 2 {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' { //nolint:mnd
+			return false
+		}
+	}
+
+	vals, err := CheckISO7064Mod97_10.parseCheckChars(
+		s[ibanHeaderLen:]+s[:ibanHeaderLen], false)
+	if err != nil {
+		return false
+	}
+
+	return mod97Rem(vals) == 1
+}
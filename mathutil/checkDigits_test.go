@@ -0,0 +1,315 @@
+package mathutil_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestCheckDigits(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		payload  string
+		scheme   mathutil.CheckDigitScheme
+		expCheck string
+	}{
+		{
+			ID:       testhelper.MkID("Luhn"),
+			payload:  "7992739871",
+			scheme:   mathutil.CheckLuhn,
+			expCheck: "3",
+		},
+		{
+			ID:       testhelper.MkID("Luhn, card number"),
+			payload:  "4111 1111 1111 111",
+			scheme:   mathutil.CheckLuhn,
+			expCheck: "1",
+		},
+		{
+			ID:       testhelper.MkID("Verhoeff"),
+			payload:  "236",
+			scheme:   mathutil.CheckVerhoeff,
+			expCheck: "3",
+		},
+		{
+			ID:       testhelper.MkID("Verhoeff, longer"),
+			payload:  "12345",
+			scheme:   mathutil.CheckVerhoeff,
+			expCheck: "1",
+		},
+		{
+			ID:       testhelper.MkID("Damm"),
+			payload:  "572",
+			scheme:   mathutil.CheckDamm,
+			expCheck: "4",
+		},
+		{
+			ID:       testhelper.MkID("ISO 7064 MOD 11-2 (ORCID)"),
+			payload:  "0000-0002-1825-009",
+			scheme:   mathutil.CheckISO7064Mod11_2,
+			expCheck: "7",
+		},
+		{
+			ID:       testhelper.MkID("ISO 7064 MOD 11-2, X"),
+			payload:  "0000-0002-1694-233",
+			scheme:   mathutil.CheckISO7064Mod11_2,
+			expCheck: "X",
+		},
+		{
+			ID:       testhelper.MkID("ISO 7064 MOD 97-10"),
+			payload:  "794",
+			scheme:   mathutil.CheckISO7064Mod97_10,
+			expCheck: "44",
+		},
+		{
+			ID:       testhelper.MkID("ISBN-10"),
+			payload:  "0-306-40615",
+			scheme:   mathutil.CheckISBN10,
+			expCheck: "2",
+		},
+		{
+			ID:       testhelper.MkID("ISBN-10, X"),
+			payload:  "0-8044-2957",
+			scheme:   mathutil.CheckISBN10,
+			expCheck: "X",
+		},
+		{
+			ID:       testhelper.MkID("ISBN-13"),
+			payload:  "978-0-306-40615",
+			scheme:   mathutil.CheckISBN13,
+			expCheck: "7",
+		},
+		{
+			ID:       testhelper.MkID("EAN-8"),
+			payload:  "7351353",
+			scheme:   mathutil.CheckEAN,
+			expCheck: "7",
+		},
+		{
+			ID:       testhelper.MkID("UPC-A"),
+			payload:  "03600029145",
+			scheme:   mathutil.CheckEAN,
+			expCheck: "2",
+		},
+		{
+			ID: testhelper.MkID("bad character"),
+			ExpErr: testhelper.MkExpErr(
+				`invalid character ('a') for CheckLuhn`),
+			payload: "12a",
+			scheme:  mathutil.CheckLuhn,
+		},
+		{
+			ID:      testhelper.MkID("X in the payload"),
+			ExpErr:  testhelper.MkExpErr(`invalid character ('X')`),
+			payload: "030640615X",
+			scheme:  mathutil.CheckISBN10,
+		},
+		{
+			ID: testhelper.MkID("ISBN-10, too short"),
+			ExpErr: testhelper.MkExpErr(
+				"CheckISBN10 needs 9 digits before the check digit, not 8"),
+			payload: "03064061",
+			scheme:  mathutil.CheckISBN10,
+		},
+		{
+			ID: testhelper.MkID("ISBN-13, bad prefix"),
+			ExpErr: testhelper.MkExpErr(
+				"CheckISBN13 must start with 978 or 979"),
+			payload: "977030640615",
+			scheme:  mathutil.CheckISBN13,
+		},
+	}
+
+	for _, tc := range testCases {
+		check, err := mathutil.CheckDigits(tc.payload, tc.scheme)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "check digits",
+			check, tc.expCheck)
+
+		full := tc.payload + check
+		testhelper.DiffBool(t, tc.IDStr(), "valid",
+			mathutil.ValidCheckDigits(full, tc.scheme), true)
+	}
+}
+
+func TestValidCheckDigits(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		s        string
+		scheme   mathutil.CheckDigitScheme
+		expValid bool
+	}{
+		{
+			ID:       testhelper.MkID("Luhn, single digit error"),
+			s:        "79927398703",
+			scheme:   mathutil.CheckLuhn,
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("Verhoeff, transposition"),
+			s:        "2633",
+			scheme:   mathutil.CheckVerhoeff,
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("Damm, transposition"),
+			s:        "7524",
+			scheme:   mathutil.CheckDamm,
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("ISBN-10, lower case x"),
+			s:        "080442957x",
+			scheme:   mathutil.CheckISBN10,
+			expValid: true,
+		},
+		{
+			ID:       testhelper.MkID("ISBN-10, wrong length"),
+			s:        "03064061522",
+			scheme:   mathutil.CheckISBN10,
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("Luhn, X not allowed"),
+			s:        "123X",
+			scheme:   mathutil.CheckLuhn,
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("check digit only"),
+			s:        "0",
+			scheme:   mathutil.CheckDamm,
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("empty"),
+			s:        "",
+			scheme:   mathutil.CheckLuhn,
+			expValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffBool(t, tc.IDStr(), "valid",
+			mathutil.ValidCheckDigits(tc.s, tc.scheme), tc.expValid)
+	}
+}
+
+func TestCheckDigitsInt(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		v      int64
+		scheme mathutil.CheckDigitScheme
+		expVal int64
+	}{
+		{
+			ID:     testhelper.MkID("Luhn"),
+			v:      7992739871,
+			scheme: mathutil.CheckLuhn,
+			expVal: 79927398713,
+		},
+		{
+			ID:     testhelper.MkID("MOD 97-10"),
+			v:      794,
+			scheme: mathutil.CheckISO7064Mod97_10,
+			expVal: 79444,
+		},
+		{
+			ID:     testhelper.MkID("ISBN-10 with a leading zero"),
+			v:      30640615,
+			scheme: mathutil.CheckISBN10,
+			expVal: 306406152,
+		},
+		{
+			ID:     testhelper.MkID("ISBN-13"),
+			v:      978030640615,
+			scheme: mathutil.CheckISBN13,
+			expVal: 9780306406157,
+		},
+		{
+			ID: testhelper.MkID("ISBN-10, X"),
+			ExpErr: testhelper.MkExpErr(
+				"the CheckISBN10 check character is 'X' which is not a digit"),
+			v:      80442957,
+			scheme: mathutil.CheckISBN10,
+		},
+		{
+			ID:     testhelper.MkID("negative"),
+			ExpErr: testhelper.MkExpErr("the value must not be negative"),
+			v:      -1,
+			scheme: mathutil.CheckLuhn,
+		},
+		{
+			ID:     testhelper.MkID("overflow"),
+			ExpErr: testhelper.MkExpErr("the value is out of range"),
+			v:      1_000_000_000_000_000_000,
+			scheme: mathutil.CheckLuhn,
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := mathutil.CheckDigitsInt(tc.v, tc.scheme)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		testhelper.DiffInt(t, tc.IDStr(), "value", v, tc.expVal)
+		testhelper.DiffBool(t, tc.IDStr(), "valid",
+			mathutil.ValidCheckDigitsInt(v, tc.scheme), true)
+		testhelper.DiffBool(t, tc.IDStr(), "valid (corrupted)",
+			mathutil.ValidCheckDigitsInt(v+1, tc.scheme), false)
+	}
+
+	testhelper.DiffBool(t, "ValidCheckDigitsInt", "negative",
+		mathutil.ValidCheckDigitsInt(-18, mathutil.CheckLuhn), false)
+}
+
+func TestValidIBAN(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		iban     string
+		expValid bool
+	}{
+		{
+			ID:       testhelper.MkID("GB"),
+			iban:     "GB82 WEST 1234 5698 7654 32",
+			expValid: true,
+		},
+		{
+			ID:       testhelper.MkID("DE, lower case"),
+			iban:     "de89370400440532013000",
+			expValid: true,
+		},
+		{
+			ID:       testhelper.MkID("bad check digits"),
+			iban:     "GB83 WEST 1234 5698 7654 32",
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("no country code"),
+			iban:     "1282WEST12345698765432",
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("too short"),
+			iban:     "GB82",
+			expValid: false,
+		},
+		{
+			ID:       testhelper.MkID("bad character"),
+			iban:     "GB82 WEST 1234 5698 7654 3!",
+			expValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffBool(t, tc.IDStr(), "valid",
+			mathutil.ValidIBAN(tc.iban), tc.expValid)
+	}
+}
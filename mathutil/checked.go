@@ -0,0 +1,199 @@
+package mathutil
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrDivByZero is returned by DivChecked if the divisor is zero
+var ErrDivByZero = errors.New("division by zero")
+
+// AddChecked returns a+b. If the result overflows the type it returns the
+// zero value and ErrOverflow.
+func AddChecked[T constraints.Integer](a, b T) (T, error) {
+	r := a + b
+	if (b > 0 && r < a) || (b < 0 && r > a) {
+		return 0, ErrOverflow
+	}
+
+	return r, nil
+}
+
+// SubChecked returns a-b. If the result overflows the type (including a
+// negative result for an unsigned type) it returns the zero value and
+// ErrOverflow.
+func SubChecked[T constraints.Integer](a, b T) (T, error) {
+	r := a - b
+	if (b > 0 && r > a) || (b < 0 && r < a) {
+		return 0, ErrOverflow
+	}
+
+	return r, nil
+}
+
+// MulChecked returns a*b. If the result overflows the type it returns the
+// zero value and ErrOverflow.
+func MulChecked[T constraints.Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+
+	r := a * b
+	if r/b != a {
+		return 0, ErrOverflow
+	}
+
+	// the most negative value divided by -1 gives itself so the check
+	// above will not detect this case
+	if isSigned[T]() && b == ^T(0) && a == InfoOf[T]().Min {
+		return 0, ErrOverflow
+	}
+
+	return r, nil
+}
+
+// DivChecked returns a/b, truncated towards zero as for the built-in
+// division. If b is zero it returns the zero value and ErrDivByZero; if
+// the result overflows the type (only possible when dividing the most
+// negative value of a signed type by -1) it returns the zero value and
+// ErrOverflow.
+func DivChecked[T constraints.Integer](a, b T) (T, error) {
+	if b == 0 {
+		return 0, ErrDivByZero
+	}
+
+	if isSigned[T]() && b == ^T(0) && a == InfoOf[T]().Min {
+		return 0, ErrOverflow
+	}
+
+	return a / b, nil
+}
+
+// NegChecked returns -a. If the result overflows the type (the most
+// negative value of a signed type, or any non-zero value of an unsigned
+// type) it returns the zero value and ErrOverflow.
+func NegChecked[T constraints.Integer](a T) (T, error) {
+	if a == 0 {
+		return 0, nil
+	}
+
+	if !isSigned[T]() || a == InfoOf[T]().Min {
+		return 0, ErrOverflow
+	}
+
+	return -a, nil
+}
+
+// AddSaturating returns a+b, clamped to the range of the type.
+func AddSaturating[T constraints.Integer](a, b T) T {
+	r, err := AddChecked(a, b)
+	if err == nil {
+		return r
+	}
+
+	if b > 0 {
+		return InfoOf[T]().Max
+	}
+
+	return InfoOf[T]().Min
+}
+
+// SubSaturating returns a-b, clamped to the range of the type. So, for
+// instance, for an unsigned type the result is zero if b is greater than
+// a.
+func SubSaturating[T constraints.Integer](a, b T) T {
+	r, err := SubChecked(a, b)
+	if err == nil {
+		return r
+	}
+
+	if b < 0 {
+		return InfoOf[T]().Max
+	}
+
+	return InfoOf[T]().Min
+}
+
+// MulSaturating returns a*b, clamped to the range of the type.
+func MulSaturating[T constraints.Integer](a, b T) T {
+	r, err := MulChecked(a, b)
+	if err == nil {
+		return r
+	}
+
+	if (a < 0) != (b < 0) {
+		return InfoOf[T]().Min
+	}
+
+	return InfoOf[T]().Max
+}
+
+// DivSaturating returns a/b, clamped to the range of the type; dividing
+// the most negative value of a signed type by -1 gives the largest value.
+// As for the built-in division, if b is zero a panic is generated.
+func DivSaturating[T constraints.Integer](a, b T) T {
+	if b == 0 {
+		panic(fmt.Sprintf("Invalid divisor (%d), it must not be zero", b))
+	}
+
+	r, err := DivChecked(a, b)
+	if err == nil {
+		return r
+	}
+
+	return InfoOf[T]().Max
+}
+
+// NegSaturating returns -a, clamped to the range of the type. So the
+// result for the most negative value of a signed type is the largest
+// value and for any value of an unsigned type it is zero.
+func NegSaturating[T constraints.Integer](a T) T {
+	r, err := NegChecked(a)
+	if err == nil {
+		return r
+	}
+
+	if isSigned[T]() {
+		return InfoOf[T]().Max
+	}
+
+	return 0
+}
+
+// AddWrapping returns a+b, wrapping around on overflow as the built-in
+// addition does. It is the same as a+b but makes clear that any wrapping
+// is intended.
+func AddWrapping[T constraints.Integer](a, b T) T {
+	return a + b
+}
+
+// SubWrapping returns a-b, wrapping around on overflow as the built-in
+// subtraction does. It is the same as a-b but makes clear that any
+// wrapping is intended.
+func SubWrapping[T constraints.Integer](a, b T) T {
+	return a - b
+}
+
+// MulWrapping returns a*b, wrapping around on overflow as the built-in
+// multiplication does. It is the same as a*b but makes clear that any
+// wrapping is intended.
+func MulWrapping[T constraints.Integer](a, b T) T {
+	return a * b
+}
+
+// DivWrapping returns a/b as the built-in division does; dividing the
+// most negative value of a signed type by -1 wraps to give the same
+// value. It is the same as a/b but makes clear that any wrapping is
+// intended. If b is zero a panic is generated.
+func DivWrapping[T constraints.Integer](a, b T) T {
+	return a / b
+}
+
+// NegWrapping returns -a, wrapping around on overflow as the built-in
+// negation does; the most negative value of a signed type gives itself.
+// It is the same as -a but makes clear that any wrapping is intended.
+func NegWrapping[T constraints.Integer](a T) T {
+	return -a
+}
@@ -0,0 +1,284 @@
+package mathutil_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// checkedOp holds the checked, saturating and wrapping forms of a binary
+// operation
+type checkedOp[T mathutil.Number] struct {
+	name      string
+	checked   func(a, b T) (T, error)
+	saturated func(a, b T) T
+	wrapped   func(a, b T) T
+}
+
+func TestCheckedInt8(t *testing.T) {
+	add := checkedOp[int8]{
+		"add",
+		mathutil.AddChecked[int8],
+		mathutil.AddSaturating[int8],
+		mathutil.AddWrapping[int8],
+	}
+	sub := checkedOp[int8]{
+		"sub",
+		mathutil.SubChecked[int8],
+		mathutil.SubSaturating[int8],
+		mathutil.SubWrapping[int8],
+	}
+	mul := checkedOp[int8]{
+		"mul",
+		mathutil.MulChecked[int8],
+		mathutil.MulSaturating[int8],
+		mathutil.MulWrapping[int8],
+	}
+	div := checkedOp[int8]{
+		"div",
+		mathutil.DivChecked[int8],
+		mathutil.DivSaturating[int8],
+		mathutil.DivWrapping[int8],
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		op          checkedOp[int8]
+		a, b        int8
+		expOverflow bool
+		expSat      int8
+		expWrap     int8
+	}{
+		{
+			ID:      testhelper.MkID("add"),
+			op:      add,
+			a:       100,
+			b:       27,
+			expSat:  127,
+			expWrap: 127,
+		},
+		{
+			ID:          testhelper.MkID("add, overflow"),
+			op:          add,
+			a:           100,
+			b:           28,
+			expOverflow: true,
+			expSat:      math.MaxInt8,
+			expWrap:     math.MinInt8,
+		},
+		{
+			ID:          testhelper.MkID("add, negative overflow"),
+			op:          add,
+			a:           -100,
+			b:           -29,
+			expOverflow: true,
+			expSat:      math.MinInt8,
+			expWrap:     127,
+		},
+		{
+			ID:      testhelper.MkID("sub"),
+			op:      sub,
+			a:       -100,
+			b:       28,
+			expSat:  -128,
+			expWrap: -128,
+		},
+		{
+			ID:          testhelper.MkID("sub, overflow"),
+			op:          sub,
+			a:           -100,
+			b:           29,
+			expOverflow: true,
+			expSat:      math.MinInt8,
+			expWrap:     127,
+		},
+		{
+			ID:          testhelper.MkID("sub, positive overflow"),
+			op:          sub,
+			a:           0,
+			b:           math.MinInt8,
+			expOverflow: true,
+			expSat:      math.MaxInt8,
+			expWrap:     math.MinInt8,
+		},
+		{
+			ID:      testhelper.MkID("mul"),
+			op:      mul,
+			a:       -16,
+			b:       8,
+			expSat:  -128,
+			expWrap: -128,
+		},
+		{
+			ID:          testhelper.MkID("mul, overflow"),
+			op:          mul,
+			a:           16,
+			b:           8,
+			expOverflow: true,
+			expSat:      math.MaxInt8,
+			expWrap:     math.MinInt8,
+		},
+		{
+			ID:          testhelper.MkID("mul, negative overflow"),
+			op:          mul,
+			a:           -16,
+			b:           9,
+			expOverflow: true,
+			expSat:      math.MinInt8,
+			expWrap:     112,
+		},
+		{
+			ID:          testhelper.MkID("mul, MinInt8 * -1"),
+			op:          mul,
+			a:           math.MinInt8,
+			b:           -1,
+			expOverflow: true,
+			expSat:      math.MaxInt8,
+			expWrap:     math.MinInt8,
+		},
+		{
+			ID:          testhelper.MkID("mul, -1 * MinInt8"),
+			op:          mul,
+			a:           -1,
+			b:           math.MinInt8,
+			expOverflow: true,
+			expSat:      math.MaxInt8,
+			expWrap:     math.MinInt8,
+		},
+		{
+			ID:      testhelper.MkID("mul, by zero"),
+			op:      mul,
+			a:       math.MinInt8,
+			b:       0,
+			expSat:  0,
+			expWrap: 0,
+		},
+		{
+			ID:      testhelper.MkID("div"),
+			op:      div,
+			a:       -7,
+			b:       2,
+			expSat:  -3,
+			expWrap: -3,
+		},
+		{
+			ID:          testhelper.MkID("div, MinInt8 / -1"),
+			op:          div,
+			a:           math.MinInt8,
+			b:           -1,
+			expOverflow: true,
+			expSat:      math.MaxInt8,
+			expWrap:     math.MinInt8,
+		},
+	}
+
+	for _, tc := range testCases {
+		r, err := tc.op.checked(tc.a, tc.b)
+		testhelper.DiffBool(t, tc.IDStr(), "overflow",
+			errors.Is(err, mathutil.ErrOverflow), tc.expOverflow)
+
+		if !tc.expOverflow {
+			testhelper.DiffInt(t, tc.IDStr(), "checked", r, tc.expWrap)
+		}
+
+		testhelper.DiffInt(t, tc.IDStr(), "saturated",
+			tc.op.saturated(tc.a, tc.b), tc.expSat)
+		testhelper.DiffInt(t, tc.IDStr(), "wrapped",
+			tc.op.wrapped(tc.a, tc.b), tc.expWrap)
+	}
+}
+
+func TestCheckedUnsigned(t *testing.T) {
+	_, err := mathutil.AddChecked(uint8(200), 56)
+	testhelper.DiffBool(t, "AddChecked", "uint8 overflow",
+		errors.Is(err, mathutil.ErrOverflow), true)
+	testhelper.DiffInt(t, "AddSaturating", "uint8",
+		mathutil.AddSaturating(uint8(200), 56), math.MaxUint8)
+
+	_, err = mathutil.SubChecked(uint(1), 2)
+	testhelper.DiffBool(t, "SubChecked", "uint underflow",
+		errors.Is(err, mathutil.ErrOverflow), true)
+	testhelper.DiffInt(t, "SubSaturating", "uint",
+		mathutil.SubSaturating(uint(1), 2), 0)
+	testhelper.DiffInt(t, "SubWrapping", "uint",
+		mathutil.SubWrapping(uint(1), 2), math.MaxUint)
+
+	r, err := mathutil.MulChecked(uint64(1<<32), 1<<31)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "MulChecked", "uint64", r, 1<<63)
+
+	_, err = mathutil.MulChecked(uint64(1<<32), 1<<32)
+	testhelper.DiffBool(t, "MulChecked", "uint64 overflow",
+		errors.Is(err, mathutil.ErrOverflow), true)
+	testhelper.DiffInt(t, "MulSaturating", "uint64",
+		mathutil.MulSaturating(uint64(1<<32), 1<<32), uint64(math.MaxUint64))
+}
+
+func TestNegAndDivByZero(t *testing.T) {
+	_, err := mathutil.NegChecked(int16(math.MinInt16))
+	testhelper.DiffBool(t, "NegChecked", "MinInt16",
+		errors.Is(err, mathutil.ErrOverflow), true)
+	testhelper.DiffInt(t, "NegSaturating", "MinInt16",
+		mathutil.NegSaturating(int16(math.MinInt16)), math.MaxInt16)
+	testhelper.DiffInt(t, "NegWrapping", "MinInt16",
+		mathutil.NegWrapping(int16(math.MinInt16)), math.MinInt16)
+
+	r, err := mathutil.NegChecked(int16(math.MaxInt16))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "NegChecked", "MaxInt16", r, -math.MaxInt16)
+
+	_, err = mathutil.NegChecked(uint(1))
+	testhelper.DiffBool(t, "NegChecked", "uint",
+		errors.Is(err, mathutil.ErrOverflow), true)
+	testhelper.DiffInt(t, "NegSaturating", "uint",
+		mathutil.NegSaturating(uint(1)), 0)
+
+	u, err := mathutil.NegChecked(uint(0))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "NegChecked", "uint zero", u, 0)
+
+	_, err = mathutil.DivChecked(1, 0)
+	testhelper.DiffBool(t, "DivChecked", "by zero",
+		errors.Is(err, mathutil.ErrDivByZero), true)
+
+}
+
+func TestDivSaturatingByZero(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		a, b int
+	}{
+		{
+			ID: testhelper.MkID("by zero"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid divisor (0), it must not be zero"),
+			a: 1,
+			b: 0,
+		},
+		{
+			ID: testhelper.MkID("by one"),
+			a:  1,
+			b:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			mathutil.DivSaturating(tc.a, tc.b)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
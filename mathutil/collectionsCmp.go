@@ -0,0 +1,190 @@
+package mathutil
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Index2D identifies an entry in a 2-dimensional slice
+type Index2D struct {
+	Row int
+	Col int
+}
+
+// String returns a string value for the Index2D
+func (i Index2D) String() string {
+	return fmt.Sprintf("%d,%d", i.Row, i.Col)
+}
+
+// FloatMismatch records a pair of values, A and B, found at the same Key in
+// two collections which were not considered equal by the comparison
+// function. Diff is A-B.
+type FloatMismatch[K comparable, F constraints.Float] struct {
+	Key  K
+	A    F
+	B    F
+	Diff F
+}
+
+// FloatDiffs records the differences found when comparing two collections
+// of floats. Count is the total number of mismatched values; Mismatches
+// holds the first of them, up to the maximum requested. OnlyInA and OnlyInB
+// hold the keys (or indexes) present in one collection but not the other.
+type FloatDiffs[K comparable, F constraints.Float] struct {
+	Count      int
+	Mismatches []FloatMismatch[K, F]
+	OnlyInA    []K
+	OnlyInB    []K
+}
+
+// Equal returns true if no differences were found
+func (d FloatDiffs[K, F]) Equal() bool {
+	return d.Count == 0 && len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0
+}
+
+// String returns a description of the differences suitable for reporting
+// a test failure. It returns the empty string if there are no differences.
+func (d FloatDiffs[K, F]) String() string {
+	if d.Equal() {
+		return ""
+	}
+
+	var b strings.Builder
+
+	if d.Count > 0 {
+		fmt.Fprintf(&b, "%d value(s) differ:\n", d.Count)
+
+		for _, m := range d.Mismatches {
+			fmt.Fprintf(&b, "\t[%v]: %g != %g (diff: %g)\n",
+				m.Key, m.A, m.B, m.Diff)
+		}
+
+		if hidden := d.Count - len(d.Mismatches); hidden > 0 {
+			fmt.Fprintf(&b, "\t... %d more not shown\n", hidden)
+		}
+	}
+
+	if len(d.OnlyInA) > 0 {
+		fmt.Fprintf(&b, "only in the first: %v\n", d.OnlyInA)
+	}
+
+	if len(d.OnlyInB) > 0 {
+		fmt.Fprintf(&b, "only in the second: %v\n", d.OnlyInB)
+	}
+
+	return b.String()
+}
+
+// add compares the two values and records a mismatch if they differ. No
+// more than maxDiffs mismatches are recorded, if maxDiffs is negative all
+// mismatches are recorded.
+func (d *FloatDiffs[K, F]) add(
+	k K, a, b F, cmpFunc func(a, b F) bool, maxDiffs int,
+) {
+	if cmpFunc(a, b) {
+		return
+	}
+
+	d.Count++
+
+	if maxDiffs < 0 || len(d.Mismatches) < maxDiffs {
+		d.Mismatches = append(d.Mismatches,
+			FloatMismatch[K, F]{Key: k, A: a, B: b, Diff: a - b})
+	}
+}
+
+// SlicesAlmostEqual compares the two slices using the comparison function
+// (such as the one returned by AlmostEqualFunc) and returns true if they
+// have the same length and every pair of values compares as equal. It also
+// returns a description of the differences; at most maxDiffs mismatches are
+// recorded (all of them if maxDiffs is negative) but all are counted.
+func SlicesAlmostEqual[F constraints.Float](
+	a, b []F, cmpFunc func(a, b F) bool, maxDiffs int,
+) (bool, FloatDiffs[int, F]) {
+	var d FloatDiffs[int, F]
+
+	for i := range min(len(a), len(b)) {
+		d.add(i, a[i], b[i], cmpFunc, maxDiffs)
+	}
+
+	for i := len(b); i < len(a); i++ {
+		d.OnlyInA = append(d.OnlyInA, i)
+	}
+
+	for i := len(a); i < len(b); i++ {
+		d.OnlyInB = append(d.OnlyInB, i)
+	}
+
+	return d.Equal(), d
+}
+
+// MapsAlmostEqual compares the two maps using the comparison function and
+// returns true if they have the same keys and every pair of values compares
+// as equal. It also returns a description of the differences; the
+// mismatches are recorded in key order and at most maxDiffs are recorded
+// (all of them if maxDiffs is negative) but all are counted.
+func MapsAlmostEqual[K cmp.Ordered, F constraints.Float](
+	a, b map[K]F, cmpFunc func(a, b F) bool, maxDiffs int,
+) (bool, FloatDiffs[K, F]) {
+	var d FloatDiffs[K, F]
+
+	for _, k := range slices.Sorted(maps.Keys(a)) {
+		bv, ok := b[k]
+		if !ok {
+			d.OnlyInA = append(d.OnlyInA, k)
+			continue
+		}
+
+		d.add(k, a[k], bv, cmpFunc, maxDiffs)
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(b)) {
+		if _, ok := a[k]; !ok {
+			d.OnlyInB = append(d.OnlyInB, k)
+		}
+	}
+
+	return d.Equal(), d
+}
+
+// Slices2DAlmostEqual compares the two 2-dimensional slices using the
+// comparison function and returns true if they have the same shape and
+// every pair of values compares as equal. The rows need not all be the
+// same length. It also returns a description of the differences; at most
+// maxDiffs mismatches are recorded (all of them if maxDiffs is negative)
+// but all are counted.
+func Slices2DAlmostEqual[F constraints.Float](
+	a, b [][]F, cmpFunc func(a, b F) bool, maxDiffs int,
+) (bool, FloatDiffs[Index2D, F]) {
+	var d FloatDiffs[Index2D, F]
+
+	for r := range max(len(a), len(b)) {
+		var rowA, rowB []F
+		if r < len(a) {
+			rowA = a[r]
+		}
+
+		if r < len(b) {
+			rowB = b[r]
+		}
+
+		for c := range min(len(rowA), len(rowB)) {
+			d.add(Index2D{Row: r, Col: c}, rowA[c], rowB[c], cmpFunc, maxDiffs)
+		}
+
+		for c := len(rowB); c < len(rowA); c++ {
+			d.OnlyInA = append(d.OnlyInA, Index2D{Row: r, Col: c})
+		}
+
+		for c := len(rowA); c < len(rowB); c++ {
+			d.OnlyInB = append(d.OnlyInB, Index2D{Row: r, Col: c})
+		}
+	}
+
+	return d.Equal(), d
+}
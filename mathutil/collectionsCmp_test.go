@@ -0,0 +1,124 @@
+package mathutil_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSlicesAlmostEqual(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		a, b       []float64
+		maxDiffs   int
+		expEqual   bool
+		expCount   int
+		expShown   int
+		expOnlyInA []int
+		expOnlyInB []int
+		expString  string
+	}{
+		{
+			ID:       testhelper.MkID("equal"),
+			a:        []float64{1, 2, 3},
+			b:        []float64{1, 2, 3.0000001},
+			maxDiffs: 5,
+			expEqual: true,
+		},
+		{
+			ID:       testhelper.MkID("both empty"),
+			maxDiffs: 5,
+			expEqual: true,
+		},
+		{
+			ID:        testhelper.MkID("one mismatch"),
+			a:         []float64{1, 2, 3},
+			b:         []float64{1, 2.5, 3},
+			maxDiffs:  5,
+			expCount:  1,
+			expShown:  1,
+			expString: "1 value(s) differ:\n\t[1]: 2 != 2.5 (diff: -0.5)\n",
+		},
+		{
+			ID:       testhelper.MkID("more mismatches than shown"),
+			a:        []float64{1, 2, 3},
+			b:        []float64{2, 3, 4},
+			maxDiffs: 2,
+			expCount: 3,
+			expShown: 2,
+			expString: "3 value(s) differ:\n" +
+				"\t[0]: 1 != 2 (diff: -1)\n" +
+				"\t[1]: 2 != 3 (diff: -1)\n" +
+				"\t... 1 more not shown\n",
+		},
+		{
+			ID:         testhelper.MkID("different lengths"),
+			a:          []float64{1, 2, 3},
+			b:          []float64{1},
+			maxDiffs:   -1,
+			expOnlyInA: []int{1, 2},
+			expString:  "only in the first: [1 2]\n",
+		},
+		{
+			ID:         testhelper.MkID("different lengths, reversed"),
+			a:          []float64{1},
+			b:          []float64{1, 2},
+			maxDiffs:   -1,
+			expOnlyInB: []int{1},
+			expString:  "only in the second: [1]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		eq, d := mathutil.SlicesAlmostEqual(tc.a, tc.b,
+			mathutil.AlmostEqualFunc(1e-6), tc.maxDiffs)
+		testhelper.DiffBool(t, tc.IDStr(), "equal", eq, tc.expEqual)
+		testhelper.DiffInt(t, tc.IDStr(), "count", d.Count, tc.expCount)
+		testhelper.DiffInt(t, tc.IDStr(), "mismatches shown",
+			len(d.Mismatches), tc.expShown)
+		testhelper.DiffSlice(t, tc.IDStr(), "only in a",
+			d.OnlyInA, tc.expOnlyInA)
+		testhelper.DiffSlice(t, tc.IDStr(), "only in b",
+			d.OnlyInB, tc.expOnlyInB)
+		testhelper.DiffString(t, tc.IDStr(), "string", d.String(), tc.expString)
+	}
+}
+
+func TestMapsAlmostEqual(t *testing.T) {
+	a := map[string]float64{"a": 100, "b": 200, "c": 300}
+	b := map[string]float64{"b": 201, "c": 330, "d": 400}
+
+	eq, d := mathutil.MapsAlmostEqual(a, b,
+		mathutil.WithinNPercentFunc(1), -1)
+
+	const id = "maps"
+
+	testhelper.DiffBool(t, id, "equal", eq, false)
+	testhelper.DiffString(t, id, "string", d.String(),
+		"1 value(s) differ:\n"+
+			"\t[c]: 300 != 330 (diff: -30)\n"+
+			"only in the first: [a]\n"+
+			"only in the second: [d]\n")
+}
+
+func TestSlices2DAlmostEqual(t *testing.T) {
+	a := [][]float64{{1, 2}, {3, 4}, {5}}
+	b := [][]float64{{1, 2}, {3, 4.5, 6}}
+
+	eq, d := mathutil.Slices2DAlmostEqual(a, b,
+		mathutil.AlmostEqualFunc(0.1), 10)
+
+	const id = "2D slices"
+
+	testhelper.DiffBool(t, id, "equal", eq, false)
+	testhelper.DiffString(t, id, "string", d.String(),
+		"1 value(s) differ:\n"+
+			"\t[1,1]: 4 != 4.5 (diff: -0.5)\n"+
+			"only in the first: [2,0]\n"+
+			"only in the second: [1,2]\n")
+
+	eq, _ = mathutil.Slices2DAlmostEqual(a, a,
+		mathutil.AlmostEqualFunc(0.1), 10)
+	testhelper.DiffBool(t, id+" (identical)", "equal", eq, true)
+}
@@ -0,0 +1,192 @@
+package mathutil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// These are the errors that Convert can return, each wrapped with the
+// details of the value and the type. Use errors.Is to check for them.
+var (
+	// ErrOverflow is returned if the value is too big, or too small, for
+	// the type.
+	ErrOverflow = errors.New("overflow: the value is out of range")
+	// ErrSignLoss is returned if the value is negative and the type is
+	// unsigned.
+	ErrSignLoss = errors.New("an unsigned value cannot be negative")
+	// ErrTruncated is returned if the value has a fractional part which
+	// an integer type cannot hold.
+	ErrTruncated = errors.New("the fractional part would be lost")
+	// ErrPrecisionLoss is returned if the value would be rounded to the
+	// nearest value that a floating point type can hold.
+	ErrPrecisionLoss = errors.New("the value cannot be held exactly")
+	// ErrNotANumber is returned if the value is a NaN and the type is an
+	// integer type.
+	ErrNotANumber = errors.New("the value is not a number")
+)
+
+// convErr returns the error wrapped with the details of the conversion
+func convErr[To, From Number](v From, err error) error {
+	return fmt.Errorf("cannot convert %v (%T) to %T: %w", v, v, To(0), err)
+}
+
+// Convert returns the value v converted to the type To. If the value
+// cannot be held exactly in To it returns the zero value and a non-nil
+// error which wraps one of ErrOverflow, ErrSignLoss, ErrTruncated,
+// ErrPrecisionLoss or ErrNotANumber.
+//
+// Infinite and NaN values can be converted between the floating point
+// types but an infinite value is out of range of any integer type. Note
+// that converting a large integer to a floating point type may lose
+// precision; for instance 1<<53 + 1 cannot be held exactly in a float64.
+func Convert[To, From Number](v From) (To, error) {
+	var err error
+
+	switch {
+	case !isFloat[From]():
+		err = convFromInt[To](v)
+	case isFloat[To]():
+		err = convFloatToFloat[To](float64(v))
+	default:
+		err = convFloatToInt[To](float64(v))
+	}
+
+	if err != nil {
+		return 0, convErr[To](v, err)
+	}
+
+	return To(v), nil
+}
+
+// convFromInt returns a non-nil error if the integer value cannot be held
+// exactly in the type To
+func convFromInt[To, From Number](v From) error {
+	toInfo := InfoOf[To]()
+
+	mag, neg := magnitudeOf(v)
+	if neg && !toInfo.Signed {
+		return ErrSignLoss
+	}
+
+	if toInfo.Float {
+		if mag > toInfo.MaxExactInt &&
+			bits.Len64(mag)-bits.TrailingZeros64(mag) > toInfo.MantissaBits+1 {
+			return ErrPrecisionLoss
+		}
+
+		return nil
+	}
+
+	limit := toInfo.MaxExactInt
+	if neg {
+		limit++
+	}
+
+	if mag > limit {
+		return ErrOverflow
+	}
+
+	return nil
+}
+
+// magnitudeOf returns the magnitude of the integer value and true if it
+// is negative. It is the same as magnitude but for a value of a type known
+// only to be a Number.
+func magnitudeOf[T Number](v T) (uint64, bool) {
+	if v < 0 {
+		return uint64(-(int64(v) + 1)) + 1, true //nolint:gosec
+	}
+
+	return uint64(v), false
+}
+
+// convFloatToFloat returns a non-nil error if the value cannot be held
+// exactly in the floating point type To
+func convFloatToFloat[To Number](f float64) error {
+	if math.IsNaN(f) {
+		return nil
+	}
+
+	r := float64(To(f))
+	if r == f {
+		return nil
+	}
+
+	if math.IsInf(r, 0) {
+		return ErrOverflow
+	}
+
+	return ErrPrecisionLoss
+}
+
+// convFloatToInt returns a non-nil error if the value cannot be held
+// exactly in the integer type To
+func convFloatToInt[To Number](f float64) error {
+	toInfo := InfoOf[To]()
+
+	if math.IsNaN(f) {
+		return ErrNotANumber
+	}
+
+	if f < 0 && !toInfo.Signed {
+		return ErrSignLoss
+	}
+
+	if math.IsInf(f, 0) {
+		return ErrOverflow
+	}
+
+	trunc := math.Trunc(f)
+
+	// the bounds are powers of two and so are exact as float64 values
+	upper := math.Ldexp(1, toInfo.Bits)
+	lower := 0.0
+
+	if toInfo.Signed {
+		upper = math.Ldexp(1, toInfo.Bits-1)
+		lower = -upper
+	}
+
+	if trunc >= upper || trunc < lower {
+		return ErrOverflow
+	}
+
+	if trunc != f {
+		return ErrTruncated
+	}
+
+	return nil
+}
+
+// ConvertSaturating returns the value v converted to the type To. Unlike
+// Convert it always returns a value: a value which is out of range is
+// clamped to the smallest or largest finite value of To (a negative value
+// converted to an unsigned type gives zero); a fractional part is
+// discarded, rounding towards zero; a value which cannot be held exactly
+// in a floating point type is rounded to the nearest value that can; and a
+// NaN converted to an integer type gives zero. Infinite and NaN values are
+// preserved when converting between the floating point types.
+func ConvertSaturating[To, From Number](v From) To {
+	r, err := Convert[To](v)
+
+	switch {
+	case err == nil:
+		return r
+	case errors.Is(err, ErrOverflow):
+		toInfo := InfoOf[To]()
+		if v < 0 {
+			return toInfo.Min
+		}
+
+		return toInfo.Max
+	case errors.Is(err, ErrTruncated):
+		return To(math.Trunc(float64(v)))
+	case errors.Is(err, ErrPrecisionLoss):
+		return To(v)
+	}
+
+	// ErrSignLoss or ErrNotANumber
+	return 0
+}
@@ -0,0 +1,284 @@
+package mathutil_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// convResult returns a func which converts v to To and returns the result
+// as an any so that conversions between different types can be tested in
+// a single table
+func convResult[To, From mathutil.Number](v From) func() (any, error) {
+	return func() (any, error) {
+		r, err := mathutil.Convert[To](v)
+		return r, err
+	}
+}
+
+// convSat returns a func which converts v to To with ConvertSaturating and
+// returns the result as an any
+func convSat[To, From mathutil.Number](v From) func() any {
+	return func() any {
+		return mathutil.ConvertSaturating[To](v)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		conv    func() (any, error)
+		expVal  any
+		expKind error
+	}{
+		{
+			ID:     testhelper.MkID("int64 to int8"),
+			conv:   convResult[int8](int64(-128)),
+			expVal: int8(-128),
+		},
+		{
+			ID: testhelper.MkID("int64 to int8, too big"),
+			ExpErr: testhelper.MkExpErr(
+				"cannot convert 128 (int64) to int8",
+				"overflow: the value is out of range"),
+			conv:    convResult[int8](int64(128)),
+			expVal:  int8(0),
+			expKind: mathutil.ErrOverflow,
+		},
+		{
+			ID:      testhelper.MkID("int64 to int8, too small"),
+			ExpErr:  testhelper.MkExpErr("overflow"),
+			conv:    convResult[int8](int64(-129)),
+			expVal:  int8(0),
+			expKind: mathutil.ErrOverflow,
+		},
+		{
+			ID:     testhelper.MkID("uint64 to int64"),
+			conv:   convResult[int64](uint64(math.MaxInt64)),
+			expVal: int64(math.MaxInt64),
+		},
+		{
+			ID:      testhelper.MkID("uint64 to int64, too big"),
+			ExpErr:  testhelper.MkExpErr("overflow"),
+			conv:    convResult[int64](uint64(math.MaxInt64 + 1)),
+			expVal:  int64(0),
+			expKind: mathutil.ErrOverflow,
+		},
+		{
+			ID:      testhelper.MkID("int to uint32, negative"),
+			ExpErr:  testhelper.MkExpErr("an unsigned value cannot be negative"),
+			conv:    convResult[uint32](-1),
+			expVal:  uint32(0),
+			expKind: mathutil.ErrSignLoss,
+		},
+		{
+			ID:     testhelper.MkID("MinInt64 to float64"),
+			conv:   convResult[float64](int64(math.MinInt64)),
+			expVal: float64(math.MinInt64),
+		},
+		{
+			ID:     testhelper.MkID("2^53 to float64"),
+			conv:   convResult[float64](int64(1 << 53)),
+			expVal: float64(1 << 53),
+		},
+		{
+			ID:      testhelper.MkID("2^53+1 to float64"),
+			ExpErr:  testhelper.MkExpErr("the value cannot be held exactly"),
+			conv:    convResult[float64](int64(1<<53 + 1)),
+			expVal:  float64(0),
+			expKind: mathutil.ErrPrecisionLoss,
+		},
+		{
+			ID:     testhelper.MkID("MaxUint64 - 2047 to float64"),
+			conv:   convResult[float64](uint64(math.MaxUint64 - 2047)),
+			expVal: float64(math.MaxUint64 - 2047),
+		},
+		{
+			ID:      testhelper.MkID("16777217 to float32"),
+			ExpErr:  testhelper.MkExpErr("the value cannot be held exactly"),
+			conv:    convResult[float32](int32(1<<24 + 1)),
+			expVal:  float32(0),
+			expKind: mathutil.ErrPrecisionLoss,
+		},
+		{
+			ID:     testhelper.MkID("float64 to int32"),
+			conv:   convResult[int32](float64(-2147483648)),
+			expVal: int32(math.MinInt32),
+		},
+		{
+			ID:      testhelper.MkID("float64 to int32, fraction"),
+			ExpErr:  testhelper.MkExpErr("the fractional part would be lost"),
+			conv:    convResult[int32](2.5),
+			expVal:  int32(0),
+			expKind: mathutil.ErrTruncated,
+		},
+		{
+			ID:      testhelper.MkID("float64 to int32, too big"),
+			ExpErr:  testhelper.MkExpErr("overflow"),
+			conv:    convResult[int32](2147483648.0),
+			expVal:  int32(0),
+			expKind: mathutil.ErrOverflow,
+		},
+		{
+			ID:      testhelper.MkID("float64 to uint64, 2^64"),
+			ExpErr:  testhelper.MkExpErr("overflow"),
+			conv:    convResult[uint64](math.Ldexp(1, 64)),
+			expVal:  uint64(0),
+			expKind: mathutil.ErrOverflow,
+		},
+		{
+			ID:      testhelper.MkID("float64 to uint8, negative fraction"),
+			ExpErr:  testhelper.MkExpErr("an unsigned value cannot be negative"),
+			conv:    convResult[uint8](-0.5),
+			expVal:  uint8(0),
+			expKind: mathutil.ErrSignLoss,
+		},
+		{
+			ID:      testhelper.MkID("NaN to int"),
+			ExpErr:  testhelper.MkExpErr("the value is not a number"),
+			conv:    convResult[int](math.NaN()),
+			expVal:  0,
+			expKind: mathutil.ErrNotANumber,
+		},
+		{
+			ID:      testhelper.MkID("Inf to int"),
+			ExpErr:  testhelper.MkExpErr("overflow"),
+			conv:    convResult[int](math.Inf(1)),
+			expVal:  0,
+			expKind: mathutil.ErrOverflow,
+		},
+		{
+			ID:     testhelper.MkID("float64 to float32"),
+			conv:   convResult[float32](0.5),
+			expVal: float32(0.5),
+		},
+		{
+			ID:     testhelper.MkID("Inf to float32"),
+			conv:   convResult[float32](math.Inf(-1)),
+			expVal: float32(math.Inf(-1)),
+		},
+		{
+			ID:      testhelper.MkID("float64 to float32, inexact"),
+			ExpErr:  testhelper.MkExpErr("the value cannot be held exactly"),
+			conv:    convResult[float32](0.1),
+			expVal:  float32(0),
+			expKind: mathutil.ErrPrecisionLoss,
+		},
+		{
+			ID:      testhelper.MkID("float64 to float32, underflow"),
+			ExpErr:  testhelper.MkExpErr("the value cannot be held exactly"),
+			conv:    convResult[float32](1e-300),
+			expVal:  float32(0),
+			expKind: mathutil.ErrPrecisionLoss,
+		},
+		{
+			ID:      testhelper.MkID("float64 to float32, too big"),
+			ExpErr:  testhelper.MkExpErr("overflow"),
+			conv:    convResult[float32](1e300),
+			expVal:  float32(0),
+			expKind: mathutil.ErrOverflow,
+		},
+		{
+			ID:     testhelper.MkID("float32 to float64"),
+			conv:   convResult[float64](float32(0.1)),
+			expVal: float64(float32(0.1)),
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.conv()
+		if v != tc.expVal {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: expected %v (%T), got %v (%T)",
+				tc.expVal, tc.expVal, v, v)
+		}
+
+		if testhelper.CheckExpErr(t, err, tc) && err != nil {
+			testhelper.DiffBool(t, tc.IDStr(), "error kind",
+				errors.Is(err, tc.expKind), true)
+		}
+	}
+
+	v, err := mathutil.Convert[float64](math.NaN())
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffBool(t, "Convert", "NaN to float64", math.IsNaN(v), true)
+}
+
+func TestConvertSaturating(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		conv   func() any
+		expVal any
+	}{
+		{
+			ID:     testhelper.MkID("in range"),
+			conv:   convSat[int8](int64(-5)),
+			expVal: int8(-5),
+		},
+		{
+			ID:     testhelper.MkID("too big"),
+			conv:   convSat[int8](int64(1000)),
+			expVal: int8(math.MaxInt8),
+		},
+		{
+			ID:     testhelper.MkID("too small"),
+			conv:   convSat[int8](int64(-1000)),
+			expVal: int8(math.MinInt8),
+		},
+		{
+			ID:     testhelper.MkID("negative to unsigned"),
+			conv:   convSat[uint16](int64(-1000)),
+			expVal: uint16(0),
+		},
+		{
+			ID:     testhelper.MkID("fraction"),
+			conv:   convSat[int](-2.75),
+			expVal: -2,
+		},
+		{
+			ID:     testhelper.MkID("float too big"),
+			conv:   convSat[uint8](300.5),
+			expVal: uint8(math.MaxUint8),
+		},
+		{
+			ID:     testhelper.MkID("Inf"),
+			conv:   convSat[int64](math.Inf(-1)),
+			expVal: int64(math.MinInt64),
+		},
+		{
+			ID:     testhelper.MkID("NaN"),
+			conv:   convSat[int32](math.NaN()),
+			expVal: int32(0),
+		},
+		{
+			ID:     testhelper.MkID("inexact"),
+			conv:   convSat[float32](0.1),
+			expVal: float32(0.1),
+		},
+		{
+			ID:     testhelper.MkID("float too big for float32"),
+			conv:   convSat[float32](-1e300),
+			expVal: float32(-math.MaxFloat32),
+		},
+		{
+			ID:     testhelper.MkID("big integer to float64"),
+			conv:   convSat[float64](int64(1<<53 + 1)),
+			expVal: float64(1 << 53),
+		},
+	}
+
+	for _, tc := range testCases {
+		if v := tc.conv(); v != tc.expVal {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: expected %v (%T), got %v (%T)",
+				tc.expVal, tc.expVal, v, v)
+		}
+	}
+}
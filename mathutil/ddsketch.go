@@ -0,0 +1,467 @@
+package mathutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// ddSketchVersion is the version of the binary encoding of a DDSketch
+const ddSketchVersion = 1
+
+// ddSketchMinIndexable is the smallest magnitude that is given its own
+// bucket, smaller values are counted as zero. It is the smallest normal
+// float64.
+const ddSketchMinIndexable = 0x1p-1022
+
+var (
+	errSketchEmpty      = errors.New("the sketch has no values")
+	errSketchMismatch   = errors.New("the sketches have different accuracies")
+	errSketchBadVersion = errors.New("unknown DDSketch encoding version")
+	errSketchTruncated  = errors.New("the DDSketch encoding is truncated")
+	errSketchTrailing   = errors.New("the DDSketch encoding has trailing bytes")
+)
+
+// DDSketch is a quantile sketch which records a summary of a stream of
+// values from which the quantiles can be estimated to a given relative
+// accuracy. It uses the DDSketch algorithm of Masson, Rim and Lee (2019):
+// each value is counted in a bucket whose bounds grow geometrically so that
+// any value reported lies within the accuracy of the true quantile
+// (expressed as a percentage of it). The memory used grows with the
+// logarithm of the range of values rather than with their number.
+//
+// Two sketches with the same accuracy can be merged, so sketches built in
+// different processes can be serialised (using MarshalBinary), sent to one
+// place and combined there.
+//
+// Values that are NaN or infinite are ignored. A DDSketch should be created
+// with NewDDSketch; the zero value can only be used with UnmarshalBinary.
+type DDSketch struct {
+	accuracy float64
+	gamma    float64
+	logGamma float64
+
+	pos       map[int]uint64
+	neg       map[int]uint64
+	zeroCount uint64
+
+	count  uint64
+	sum    float64
+	minVal float64
+	maxVal float64
+}
+
+// NewDDSketch returns a pointer to a new DDSketch which will report
+// quantiles to within accuracy percent of their true value. It will panic
+// if the accuracy is not greater than zero and less than 100.
+func NewDDSketch(accuracy float64) *DDSketch {
+	if !(accuracy > 0 && accuracy < 100) {
+		panic(fmt.Sprintf(
+			"Invalid accuracy (%g), it must be greater than zero and less than 100",
+			accuracy))
+	}
+
+	s := &DDSketch{}
+	s.init(accuracy)
+
+	return s
+}
+
+// init sets the accuracy of the sketch and clears any values
+func (s *DDSketch) init(accuracy float64) {
+	alpha := FromPercent(accuracy)
+
+	*s = DDSketch{
+		accuracy: accuracy,
+		gamma:    (1 + alpha) / (1 - alpha),
+		pos:      map[int]uint64{},
+		neg:      map[int]uint64{},
+	}
+	s.logGamma = math.Log(s.gamma)
+}
+
+// index returns the bucket index for the (positive) magnitude v
+func (s *DDSketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / s.logGamma))
+}
+
+// value returns the representative magnitude for the bucket with index i.
+// This is the point in the bucket with the same relative distance to either
+// bound.
+func (s *DDSketch) value(i int) float64 {
+	return 2 * math.Pow(s.gamma, float64(i)) / (1 + s.gamma) //nolint:mnd
+}
+
+// Accuracy returns the accuracy of the sketch as a percentage
+func (s *DDSketch) Accuracy() float64 {
+	return s.accuracy
+}
+
+// SigFigs returns the number of significant figures justified by the
+// accuracy of the sketch. It can be passed to RoundSigFigs or
+// FmtValsForSigFigs when reporting the quantiles.
+func (s *DDSketch) SigFigs() uint8 {
+	return uint8(max(1, math.Floor(-math.Log10(FromPercent(s.accuracy)))))
+}
+
+// Add adds the value to the sketch
+func (s *DDSketch) Add(v float64) {
+	s.AddN(v, 1)
+}
+
+// AddAll adds each of the values to the sketch
+func (s *DDSketch) AddAll(vals ...float64) {
+	for _, v := range vals {
+		s.AddN(v, 1)
+	}
+}
+
+// AddN adds the value to the sketch n times
+func (s *DDSketch) AddN(v float64, n uint64) {
+	if n == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+
+	switch {
+	case v >= ddSketchMinIndexable:
+		s.pos[s.index(v)] += n
+	case v <= -ddSketchMinIndexable:
+		s.neg[s.index(-v)] += n
+	default:
+		s.zeroCount += n
+	}
+
+	if s.count == 0 {
+		s.minVal, s.maxVal = v, v
+	} else {
+		s.minVal = min(s.minVal, v)
+		s.maxVal = max(s.maxVal, v)
+	}
+
+	s.count += n
+	s.sum += v * float64(n)
+}
+
+// Merge adds the values recorded in other into s. It returns a non-nil
+// error if the two sketches have different accuracies, in which case s is
+// not changed.
+func (s *DDSketch) Merge(other *DDSketch) error {
+	if s.gamma != other.gamma {
+		return errSketchMismatch
+	}
+
+	if other.count == 0 {
+		return nil
+	}
+
+	for i, n := range other.pos {
+		s.pos[i] += n
+	}
+
+	for i, n := range other.neg {
+		s.neg[i] += n
+	}
+
+	if s.count == 0 {
+		s.minVal, s.maxVal = other.minVal, other.maxVal
+	} else {
+		s.minVal = min(s.minVal, other.minVal)
+		s.maxVal = max(s.maxVal, other.maxVal)
+	}
+
+	s.zeroCount += other.zeroCount
+	s.count += other.count
+	s.sum += other.sum
+
+	return nil
+}
+
+// Count returns the number of values added to the sketch
+func (s *DDSketch) Count() uint64 {
+	return s.count
+}
+
+// Sum returns the sum of the values added to the sketch
+func (s *DDSketch) Sum() float64 {
+	return s.sum
+}
+
+// Min returns the least value added to the sketch and true. If no values
+// have been added it returns false.
+func (s *DDSketch) Min() (float64, bool) {
+	return s.minVal, s.count > 0
+}
+
+// Max returns the greatest value added to the sketch and true. If no values
+// have been added it returns false.
+func (s *DDSketch) Max() (float64, bool) {
+	return s.maxVal, s.count > 0
+}
+
+// Quantile returns an estimate of the q'th quantile of the values added to
+// the sketch. The quantile, q, must be in the range [0, 1]. The estimate is
+// within the accuracy of the sketch of the value that Quantile with the
+// QuantileInvertedCDF method would give if all the values had been kept;
+// the estimate is never outside the range of the values.
+//
+// A non-nil error is returned if the sketch is empty or if q is out of
+// range.
+func (s *DDSketch) Quantile(q float64) (float64, error) {
+	if s.count == 0 {
+		return 0, errSketchEmpty
+	}
+
+	if !(q >= 0 && q <= 1) {
+		return 0, fmt.Errorf("bad quantile (%g), it must be in the range [0, 1]",
+			q)
+	}
+
+	if q == 0 {
+		return s.minVal, nil
+	}
+
+	if q == 1 {
+		return s.maxVal, nil
+	}
+
+	// rank is the number of values before the one we want
+	rank := uint64(math.Ceil(q*float64(s.count))) - 1
+
+	var v float64
+
+	seen := uint64(0)
+	found := false
+
+	// the negative values, from the most negative
+	for _, i := range slices.Backward(slices.Sorted(maps.Keys(s.neg))) {
+		seen += s.neg[i]
+		if seen > rank {
+			v, found = -s.value(i), true
+			break
+		}
+	}
+
+	if !found {
+		seen += s.zeroCount
+		found = seen > rank
+	}
+
+	if !found {
+		for _, i := range slices.Sorted(maps.Keys(s.pos)) {
+			seen += s.pos[i]
+			if seen > rank {
+				v = s.value(i)
+				break
+			}
+		}
+	}
+
+	return min(max(v, s.minVal), s.maxVal), nil
+}
+
+// Quantiles returns an estimate of the quantiles of the values added to
+// the sketch for each of the qs.
+func (s *DDSketch) Quantiles(qs ...float64) ([]float64, error) {
+	results := make([]float64, 0, len(qs))
+
+	for _, q := range qs {
+		v, err := s.Quantile(q)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, v)
+	}
+
+	return results, nil
+}
+
+// Percentile returns an estimate of the pct'th percentile of the values
+// added to the sketch. The percentage, pct, must be in the range [0, 100]
+// and is converted to a quantile using FromPercent.
+func (s *DDSketch) Percentile(pct float64) (float64, error) {
+	return s.Quantile(FromPercent(pct))
+}
+
+// appendBuckets appends the encoding of the buckets to b. The bucket
+// indexes are written in increasing order as the difference from the
+// previous index.
+func appendBuckets(b []byte, buckets map[int]uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(len(buckets)))
+
+	prev := 0
+	for _, i := range slices.Sorted(maps.Keys(buckets)) {
+		b = binary.AppendVarint(b, int64(i-prev))
+		b = binary.AppendUvarint(b, buckets[i])
+		prev = i
+	}
+
+	return b
+}
+
+// MarshalBinary encodes the sketch into a binary form. This can be
+// decoded by UnmarshalBinary, typically so that sketches can be merged
+// in another process.
+func (s *DDSketch) MarshalBinary() ([]byte, error) {
+	b := []byte{ddSketchVersion}
+	b = binary.BigEndian.AppendUint64(b, math.Float64bits(s.accuracy))
+	b = binary.AppendUvarint(b, s.count)
+	b = binary.AppendUvarint(b, s.zeroCount)
+	b = binary.BigEndian.AppendUint64(b, math.Float64bits(s.sum))
+	b = binary.BigEndian.AppendUint64(b, math.Float64bits(s.minVal))
+	b = binary.BigEndian.AppendUint64(b, math.Float64bits(s.maxVal))
+	b = appendBuckets(b, s.pos)
+	b = appendBuckets(b, s.neg)
+
+	return b, nil
+}
+
+// sketchDecoder reads the parts of an encoded DDSketch, recording the
+// first error found
+type sketchDecoder struct {
+	b   []byte
+	err error
+}
+
+// uvarint reads an unsigned varint
+func (d *sketchDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errSketchTruncated
+		return 0
+	}
+
+	d.b = d.b[n:]
+
+	return v
+}
+
+// varint reads a signed varint
+func (d *sketchDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errSketchTruncated
+		return 0
+	}
+
+	d.b = d.b[n:]
+
+	return v
+}
+
+// float reads a big-endian float64
+func (d *sketchDecoder) float() float64 {
+	const floatBytes = float64Bits / bitsInByte
+
+	if d.err != nil {
+		return 0
+	}
+
+	if len(d.b) < floatBytes {
+		d.err = errSketchTruncated
+		return 0
+	}
+
+	v := math.Float64frombits(binary.BigEndian.Uint64(d.b))
+	d.b = d.b[floatBytes:]
+
+	return v
+}
+
+// buckets reads an encoded set of buckets
+func (d *sketchDecoder) buckets() map[int]uint64 {
+	buckets := map[int]uint64{}
+
+	count := d.uvarint()
+
+	i := 0
+	for range count {
+		if d.err != nil {
+			break
+		}
+
+		i += int(d.varint())
+		buckets[i] = d.uvarint()
+	}
+
+	return buckets
+}
+
+// bucketTotal returns the total of the counts in the buckets and true. If
+// the total overflows it returns false.
+func bucketTotal(buckets map[int]uint64, total uint64) (uint64, bool) {
+	for _, c := range buckets {
+		var err error
+		if total, err = AddChecked(total, c); err != nil {
+			return 0, false
+		}
+	}
+
+	return total, true
+}
+
+// UnmarshalBinary decodes the binary form of a sketch, as generated by
+// MarshalBinary, replacing the contents of s. An error is returned, and s
+// is left unchanged, if the data is not a complete and consistent encoding
+// of a sketch.
+func (s *DDSketch) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errSketchTruncated
+	}
+
+	if data[0] != ddSketchVersion {
+		return errSketchBadVersion
+	}
+
+	d := sketchDecoder{b: data[1:]}
+
+	accuracy := d.float()
+	count := d.uvarint()
+	zeroCount := d.uvarint()
+	sum := d.float()
+	minVal := d.float()
+	maxVal := d.float()
+	pos := d.buckets()
+	neg := d.buckets()
+
+	if d.err != nil {
+		return d.err
+	}
+
+	if len(d.b) != 0 {
+		return errSketchTrailing
+	}
+
+	total, ok := bucketTotal(pos, zeroCount)
+	if ok {
+		total, ok = bucketTotal(neg, total)
+	}
+
+	if !ok || total != count {
+		return fmt.Errorf(
+			"bad DDSketch count (%d), it does not match the bucket counts",
+			count)
+	}
+
+	if !(accuracy > 0 && accuracy < 100) {
+		return fmt.Errorf("bad DDSketch accuracy (%g)", accuracy)
+	}
+
+	s.init(accuracy)
+	s.count, s.zeroCount = count, zeroCount
+	s.sum, s.minVal, s.maxVal = sum, minVal, maxVal
+	s.pos, s.neg = pos, neg
+
+	return nil
+}
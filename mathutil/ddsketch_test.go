@@ -0,0 +1,318 @@
+package mathutil_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// sketchTestQs are the quantiles checked by the DDSketch tests
+var sketchTestQs = []float64{0, 0.01, 0.1, 0.5, 0.9, 0.99, 0.999, 1}
+
+// checkSketch checks that the quantiles reported by the sketch are within
+// its accuracy of the exact quantiles of the values
+func checkSketch(t *testing.T, id string, s *mathutil.DDSketch,
+	vals []float64,
+) {
+	t.Helper()
+
+	testhelper.DiffInt(t, id, "count", s.Count(), uint64(len(vals)))
+
+	for _, q := range sketchTestQs {
+		exp, err := mathutil.Quantile(vals, q, mathutil.QuantileInvertedCDF)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", id, err)
+		}
+
+		v, err := s.Quantile(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", id, err)
+		}
+
+		if !mathutil.WithinNPercent(v, exp, s.Accuracy()) &&
+			!(v == 0 && exp == 0) {
+			t.Log(id)
+			t.Errorf("\t: quantile %g: %g is not within %g%% of %g",
+				q, v, s.Accuracy(), exp)
+		}
+	}
+}
+
+func TestDDSketch(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4)) //nolint:gosec
+
+	testCases := []struct {
+		testhelper.ID
+		accuracy float64
+		gen      func() float64
+	}{
+		{
+			ID:       testhelper.MkID("log-normal latencies, 1%"),
+			accuracy: 1,
+			gen:      func() float64 { return math.Exp(r.NormFloat64()) },
+		},
+		{
+			ID:       testhelper.MkID("log-normal latencies, 0.1%"),
+			accuracy: 0.1,
+			gen:      func() float64 { return math.Exp(r.NormFloat64()) },
+		},
+		{
+			ID:       testhelper.MkID("normal, mixed signs, 2%"),
+			accuracy: 2,
+			gen:      func() float64 { return r.NormFloat64() * 1000 },
+		},
+		{
+			ID:       testhelper.MkID("small integers with zeros, 1%"),
+			accuracy: 1,
+			gen:      func() float64 { return float64(r.IntN(5) - 1) },
+		},
+	}
+
+	for _, tc := range testCases {
+		const n = 10000
+
+		s := mathutil.NewDDSketch(tc.accuracy)
+
+		vals := make([]float64, 0, n)
+		for range n {
+			v := tc.gen()
+			vals = append(vals, v)
+			s.Add(v)
+		}
+
+		checkSketch(t, tc.IDStr(), s, vals)
+
+		minVal, ok := s.Min()
+		testhelper.DiffBool(t, tc.IDStr(), "min ok", ok, true)
+		testhelper.DiffFloat(t, tc.IDStr(), "min", minVal,
+			mathutil.MinOf(vals...), 0)
+
+		maxVal, ok := s.Max()
+		testhelper.DiffBool(t, tc.IDStr(), "max ok", ok, true)
+		testhelper.DiffFloat(t, tc.IDStr(), "max", maxVal,
+			mathutil.MaxOf(vals...), 0)
+	}
+}
+
+func TestDDSketchMerge(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6)) //nolint:gosec
+
+	const n = 5000
+
+	all := make([]float64, 0, 2*n)
+	a := mathutil.NewDDSketch(1)
+	b := mathutil.NewDDSketch(1)
+
+	for range n {
+		va, vb := math.Exp(r.NormFloat64()), 10*math.Exp(r.NormFloat64())
+		a.Add(va)
+		b.Add(vb)
+		all = append(all, va, vb)
+	}
+
+	if err := a.Merge(b); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	checkSketch(t, "merged", a, all)
+
+	err := a.Merge(mathutil.NewDDSketch(2))
+	if err == nil {
+		t.Error("an error was expected merging sketches of different accuracy")
+	}
+
+	empty := mathutil.NewDDSketch(1)
+	if err := empty.Merge(a); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	checkSketch(t, "merged into empty", empty, all)
+}
+
+func TestDDSketchMarshal(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8)) //nolint:gosec
+
+	const n = 1000
+
+	s := mathutil.NewDDSketch(0.5)
+
+	vals := make([]float64, 0, n)
+	for range n {
+		v := r.NormFloat64() * 100
+		vals = append(vals, v)
+		s.Add(v)
+	}
+
+	s.AddN(0, 3)
+	vals = append(vals, 0, 0, 0)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	var decoded mathutil.DDSketch
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffFloat(t, "unmarshalled", "accuracy",
+		decoded.Accuracy(), s.Accuracy(), 0)
+	testhelper.DiffFloat(t, "unmarshalled", "sum", decoded.Sum(), s.Sum(), 0)
+	checkSketch(t, "unmarshalled", &decoded, vals)
+
+	for _, q := range sketchTestQs {
+		exp, _ := s.Quantile(q)
+		v, _ := decoded.Quantile(q)
+		testhelper.DiffFloat(t, "unmarshalled", fmt.Sprintf("quantile %g", q),
+			v, exp, 0)
+	}
+
+	for i := range data {
+		if err := decoded.UnmarshalBinary(data[:i]); err == nil {
+			t.Errorf("truncated at %d: an error was expected", i)
+		}
+	}
+
+	bad := append([]byte{99}, data[1:]...)
+	if err := decoded.UnmarshalBinary(bad); err == nil {
+		t.Error("bad version: an error was expected")
+	}
+}
+
+func TestDDSketchUnmarshalBad(t *testing.T) {
+	s := mathutil.NewDDSketch(1)
+	s.Add(1)
+	s.Add(-2)
+	s.Add(0)
+
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// the count is the single byte following the version and the accuracy
+	const countOffset = 9
+
+	badCount := func(c byte) []byte {
+		b := append([]byte{}, data...)
+		b[countOffset] = c
+
+		return b
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		data []byte
+	}{
+		{
+			ID:   testhelper.MkID("good"),
+			data: data,
+		},
+		{
+			ID: testhelper.MkID("trailing byte"),
+			ExpErr: testhelper.MkExpErr(
+				"the DDSketch encoding has trailing bytes"),
+			data: append(append([]byte{}, data...), 0),
+		},
+		{
+			ID: testhelper.MkID("count too big"),
+			ExpErr: testhelper.MkExpErr("bad DDSketch count (4)," +
+				" it does not match the bucket counts"),
+			data: badCount(4),
+		},
+		{
+			ID: testhelper.MkID("count too small"),
+			ExpErr: testhelper.MkExpErr("bad DDSketch count (2)," +
+				" it does not match the bucket counts"),
+			data: badCount(2),
+		},
+	}
+
+	for _, tc := range testCases {
+		var decoded mathutil.DDSketch
+
+		err := decoded.UnmarshalBinary(tc.data)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestDDSketchErrs(t *testing.T) {
+	s := mathutil.NewDDSketch(1)
+
+	if _, err := s.Quantile(0.5); err == nil {
+		t.Error("empty sketch: an error was expected")
+	}
+
+	if _, ok := s.Min(); ok {
+		t.Error("empty sketch: Min should not be ok")
+	}
+
+	s.AddAll(1, 2, 3, math.NaN(), math.Inf(1))
+	testhelper.DiffInt(t, "non-finite values", "count", s.Count(), 3)
+
+	if _, err := s.Quantile(1.5); err == nil {
+		t.Error("bad quantile: an error was expected")
+	}
+
+	if _, err := s.Quantiles(0.5, -1); err == nil {
+		t.Error("bad quantiles: an error was expected")
+	}
+
+	v, err := s.Percentile(50)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffFloat(t, "Percentile", "50%", v, 2, 0.02)
+}
+
+func TestNewDDSketch(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		accuracy  float64
+		expSigFig uint8
+	}{
+		{
+			ID:        testhelper.MkID("1%"),
+			accuracy:  1,
+			expSigFig: 2,
+		},
+		{
+			ID:        testhelper.MkID("0.1%"),
+			accuracy:  0.1,
+			expSigFig: 3,
+		},
+		{
+			ID:        testhelper.MkID("25%"),
+			accuracy:  25,
+			expSigFig: 1,
+		},
+		{
+			ID: testhelper.MkID("zero"),
+			ExpPanic: testhelper.MkExpPanic("Invalid accuracy (0)," +
+				" it must be greater than zero and less than 100"),
+		},
+		{
+			ID:       testhelper.MkID("100"),
+			accuracy: 100,
+			ExpPanic: testhelper.MkExpPanic("Invalid accuracy (100)," +
+				" it must be greater than zero and less than 100"),
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			s := mathutil.NewDDSketch(tc.accuracy)
+			testhelper.DiffInt(t, tc.IDStr(), "sig figs",
+				s.SigFigs(), tc.expSigFig)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
@@ -0,0 +1,154 @@
+package mathutil
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// checkDigitBase panics if the base is not in the range [2, 64], the
+// bases for which the digits of a value can be found
+func checkDigitBase(b uint) {
+	checkBaseRange(b, maxAlphabetBase)
+}
+
+// DigitSeq returns an iterator over the digits of v in base b, from the most
+// significant to the least. The sign of v is ignored and a zero value has a
+// single zero digit. Note that the base must be between 2 and 64; if not a
+// panic is generated.
+func DigitSeq[T constraints.Integer](v T, b uint) iter.Seq[uint8] {
+	checkDigitBase(b)
+
+	mag, _ := magnitude(v)
+
+	return func(yield func(uint8) bool) {
+		powers := digitTables[b].powers
+
+		for i := digitsUint64(mag, b) - 1; i >= 0; i-- {
+			if !yield(uint8(mag / powers[i] % uint64(b))) {
+				return
+			}
+		}
+	}
+}
+
+// DigitVals returns the digits of v in base b, the most significant first.
+// The sign of v is ignored and a zero value has a single zero digit. Note
+// that the base must be between 2 and 64; if not a panic is generated.
+func DigitVals[T constraints.Integer](v T, b uint) []uint8 {
+	return slices.Collect(DigitSeq(v, b))
+}
+
+// DigitAt returns the k'th digit of v in base b, counting from the least
+// significant digit which has a k of zero. The sign of v is ignored and if k
+// is beyond the most significant digit the result is zero. Note that the
+// base must be between 2 and 64; if not a panic is generated.
+func DigitAt[T constraints.Integer](v T, b uint, k uint) uint8 {
+	checkDigitBase(b)
+
+	mag, _ := magnitude(v)
+
+	powers := digitTables[b].powers
+	if k >= uint(len(powers)) {
+		return 0
+	}
+
+	return uint8(mag / powers[k] % uint64(b))
+}
+
+// DigitSum returns the sum of the digits of v in base b. The sign of v is
+// ignored. Note that the base must be between 2 and 64; if not a panic is
+// generated.
+func DigitSum[T constraints.Integer](v T, b uint) uint64 {
+	checkDigitBase(b)
+
+	mag, _ := magnitude(v)
+
+	var sum uint64
+	for ; mag > 0; mag /= uint64(b) {
+		sum += mag % uint64(b)
+	}
+
+	return sum
+}
+
+// DigitalRoot returns the digital root of v in base b; that is, the single
+// digit found by repeatedly summing the digits. The sign of v is ignored.
+// Note that the base must be between 2 and 64; if not a panic is generated.
+func DigitalRoot[T constraints.Integer](v T, b uint) uint8 {
+	checkDigitBase(b)
+
+	mag, _ := magnitude(v)
+	if mag == 0 {
+		return 0
+	}
+
+	return uint8(1 + (mag-1)%uint64(b-1))
+}
+
+// IsPalindrome returns true if the digits of v in base b read the same in
+// either direction. The sign of v is ignored. Note that the base must be
+// between 2 and 64; if not a panic is generated.
+func IsPalindrome[T constraints.Integer](v T, b uint) bool {
+	digits := DigitVals(v, b)
+
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		if digits[i] != digits[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReverseDigits returns the value with the digits of v in base b in the
+// reverse order, keeping the sign of v; any trailing zeros of v are lost.
+// So, for instance, reversing 1230 in base 10 gives 321. A non-nil error
+// is returned if the reversed value is out of range for the type. Note that
+// the base must be between 2 and 64; if not a panic is generated.
+func ReverseDigits[T constraints.Integer](v T, b uint) (T, error) {
+	checkDigitBase(b)
+
+	mag, neg := magnitude(v)
+
+	var (
+		rev uint64
+		ok  bool
+	)
+
+	for ; mag > 0; mag /= uint64(b) {
+		if rev, ok = appendDigit(rev, mag%uint64(b), b); !ok {
+			return 0, errParseOutOfRange
+		}
+	}
+
+	return fromMagnitude[T](rev, neg)
+}
+
+// FromDigits returns the value whose digits in base b are given, the most
+// significant first. It is the inverse of DigitVals. A non-nil error is
+// returned if any digit is not valid in the base or if the value is out of
+// range for the type. Note that the base must be between 2 and 64; if not a
+// panic is generated.
+func FromDigits[T constraints.Integer](digits []uint8, b uint) (T, error) {
+	checkDigitBase(b)
+
+	var (
+		mag uint64
+		ok  bool
+	)
+
+	for _, d := range digits {
+		if uint(d) >= b {
+			return 0, fmt.Errorf("invalid digit (%d) for base %d", d, b)
+		}
+
+		if mag, ok = appendDigit(mag, uint64(d), b); !ok {
+			return 0, errParseOutOfRange
+		}
+	}
+
+	return fromMagnitude[T](mag, false)
+}
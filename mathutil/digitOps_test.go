@@ -0,0 +1,257 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestDigitVals(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		v         int64
+		base      uint
+		expDigits []uint8
+	}{
+		{
+			ID:        testhelper.MkID("zero"),
+			v:         0,
+			base:      10,
+			expDigits: []uint8{0},
+		},
+		{
+			ID:        testhelper.MkID("decimal"),
+			v:         9051,
+			base:      10,
+			expDigits: []uint8{9, 0, 5, 1},
+		},
+		{
+			ID:        testhelper.MkID("negative"),
+			v:         -9051,
+			base:      10,
+			expDigits: []uint8{9, 0, 5, 1},
+		},
+		{
+			ID:        testhelper.MkID("binary"),
+			v:         10,
+			base:      2,
+			expDigits: []uint8{1, 0, 1, 0},
+		},
+		{
+			ID:        testhelper.MkID("base 62"),
+			v:         61*62 + 7,
+			base:      62,
+			expDigits: []uint8{61, 7},
+		},
+		{
+			ID: testhelper.MkID("bad base"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid base (65), the base must be no more than 64"),
+			v:    1,
+			base: 65,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			digits := mathutil.DigitVals(tc.v, tc.base)
+			testhelper.DiffSlice(t, tc.IDStr(), "digits", digits, tc.expDigits)
+
+			v, err := mathutil.FromDigits[int64](digits, tc.base)
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: unexpected error: %v", err)
+
+				return
+			}
+
+			testhelper.DiffInt(t, tc.IDStr(), "rebuilt value",
+				v, max(tc.v, -tc.v))
+
+			for k, d := range digits {
+				testhelper.DiffInt(t, tc.IDStr(), "digit at",
+					mathutil.DigitAt(tc.v, tc.base, uint(len(digits)-1-k)), d)
+			}
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+func TestDigitSeq(t *testing.T) {
+	var got []uint8
+
+	for d := range mathutil.DigitSeq(uint64(math.MaxUint64), 10) {
+		got = append(got, d)
+		if len(got) == 3 {
+			break
+		}
+	}
+
+	testhelper.DiffSlice(t, "DigitSeq", "first 3 digits",
+		got, []uint8{1, 8, 4})
+
+	testhelper.DiffInt(t, "DigitAt", "beyond the value",
+		mathutil.DigitAt(uint64(math.MaxUint64), 10, 100), 0)
+	testhelper.DiffInt(t, "DigitAt", "most significant",
+		mathutil.DigitAt(uint64(math.MaxUint64), 10, 19), 1)
+	testhelper.DiffInt(t, "DigitAt", "MinInt64",
+		mathutil.DigitAt(int64(math.MinInt64), 10, 0), 8)
+}
+
+func TestDigitSumAndRoot(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		v       int64
+		base    uint
+		expSum  uint64
+		expRoot uint8
+	}{
+		{
+			ID:      testhelper.MkID("zero"),
+			v:       0,
+			base:    10,
+			expSum:  0,
+			expRoot: 0,
+		},
+		{
+			ID:      testhelper.MkID("decimal"),
+			v:       987654321,
+			base:    10,
+			expSum:  45,
+			expRoot: 9,
+		},
+		{
+			ID:      testhelper.MkID("negative"),
+			v:       -1234,
+			base:    10,
+			expSum:  10,
+			expRoot: 1,
+		},
+		{
+			ID:      testhelper.MkID("hex"),
+			v:       0xff,
+			base:    16,
+			expSum:  30,
+			expRoot: 15,
+		},
+		{
+			ID:      testhelper.MkID("binary"),
+			v:       0b1011,
+			base:    2,
+			expSum:  3,
+			expRoot: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffInt(t, tc.IDStr(), "digit sum",
+			mathutil.DigitSum(tc.v, tc.base), tc.expSum)
+		testhelper.DiffInt(t, tc.IDStr(), "digital root",
+			mathutil.DigitalRoot(tc.v, tc.base), tc.expRoot)
+	}
+}
+
+func TestReverseDigits(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		v         int32
+		base      uint
+		expVal    int32
+		expPalind bool
+	}{
+		{
+			ID:        testhelper.MkID("zero"),
+			v:         0,
+			base:      10,
+			expVal:    0,
+			expPalind: true,
+		},
+		{
+			ID:     testhelper.MkID("trailing zeros"),
+			v:      1230,
+			base:   10,
+			expVal: 321,
+		},
+		{
+			ID:     testhelper.MkID("negative"),
+			v:      -123,
+			base:   10,
+			expVal: -321,
+		},
+		{
+			ID:        testhelper.MkID("palindrome"),
+			v:         12321,
+			base:      10,
+			expVal:    12321,
+			expPalind: true,
+		},
+		{
+			ID:        testhelper.MkID("binary palindrome"),
+			v:         0b1001,
+			base:      2,
+			expVal:    0b1001,
+			expPalind: true,
+		},
+		{
+			ID:     testhelper.MkID("overflow"),
+			ExpErr: testhelper.MkExpErr("the value is out of range"),
+			v:      1_000_000_003,
+			base:   10,
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := mathutil.ReverseDigits(tc.v, tc.base)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffInt(t, tc.IDStr(), "reversed", v, tc.expVal)
+		}
+
+		testhelper.DiffBool(t, tc.IDStr(), "is palindrome",
+			mathutil.IsPalindrome(tc.v, tc.base), tc.expPalind)
+	}
+}
+
+func TestFromDigitsErrs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		digits []uint8
+		base   uint
+	}{
+		{
+			ID:     testhelper.MkID("bad digit"),
+			ExpErr: testhelper.MkExpErr("invalid digit (10) for base 10"),
+			digits: []uint8{1, 10},
+			base:   10,
+		},
+		{
+			ID:     testhelper.MkID("too big"),
+			ExpErr: testhelper.MkExpErr("the value is out of range"),
+			digits: []uint8{2, 5, 6},
+			base:   10,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := mathutil.FromDigits[uint8](tc.digits, tc.base)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+
+	v, err := mathutil.FromDigits[uint8]([]uint8{2, 5, 5}, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "FromDigits", "max uint8", v, 255)
+
+	v, err = mathutil.FromDigits[uint8](nil, 10)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "FromDigits", "no digits", v, 0)
+}
@@ -3,6 +3,8 @@ package mathutil
 import (
 	"fmt"
 	"math"
+	"math/big"
+	"math/bits"
 
 	"golang.org/x/exp/constraints"
 )
@@ -10,43 +12,136 @@ import (
 const (
 	base10  = 10
 	minBase = 2
+	// maxAlphabetBase is the largest base that can be used with an Alphabet
+	maxAlphabetBase = 64
 )
 
-// Digits returns the characters needed to print the value (the number of
-// digits plus potentially a sign marker)
-func Digits[T constraints.Signed](v T) int {
-	return DigitsInBase(v, base10)
+// log2Shift is the scale of the digitTable multipliers
+const log2Shift = 16
+
+// digitTable holds the values used to count the digits of a uint64 in a
+// particular base
+type digitTable struct {
+	// powers[i] is b^i for every power of b that fits in a uint64
+	powers []uint64
+	// mult is 2^log2Shift / log2(b) rounded down. The number of bits in a
+	// value multiplied by this (and shifted) gives a lower bound on the
+	// number of digits.
+	mult uint64
 }
 
-// DigitsUnsigned returns the characters needed to print the value
-func DigitsUnsigned[T constraints.Unsigned](v T) int {
-	return DigitsInBaseUnsigned(v, base10)
+// digitTables holds the digitTable for each base that can be used with an
+// Alphabet
+var digitTables = mkDigitTables()
+
+// mkDigitTables returns the digitTable for each base up to
+// maxAlphabetBase, indexed by the base
+func mkDigitTables() [maxAlphabetBase + 1]digitTable {
+	var tables [maxAlphabetBase + 1]digitTable
+
+	for b := uint64(minBase); b <= maxAlphabetBase; b++ {
+		dt := &tables[b]
+
+		dt.mult = uint64(math.Floor(
+			float64(uint64(1)<<log2Shift) / math.Log2(float64(b))))
+
+		p := uint64(1)
+		for {
+			dt.powers = append(dt.powers, p)
+
+			hi, lo := bits.Mul64(p, b)
+			if hi != 0 {
+				break
+			}
+
+			p = lo
+		}
+	}
+
+	return tables
 }
 
-// DigitsInBase returns the characters needed to print the value v in base
-// b. Note that the base must be 2 or more; if not a panic is generated.
-func DigitsInBase[T constraints.Signed](v T, b uint) int {
+// checkMinBase panics if the base is less than 2
+func checkMinBase(b uint) {
 	if b < minBase {
 		panic(fmt.Sprintf("Invalid base (%d), the base must be at least %d",
 			b, minBase))
 	}
+}
+
+// checkBaseRange panics if the base is not in the range [2, maxB]
+func checkBaseRange(b, maxB uint) {
+	checkMinBase(b)
 
+	if b > maxB {
+		panic(fmt.Sprintf("Invalid base (%d), the base must be no more than %d",
+			b, maxB))
+	}
+}
+
+// digitsUint64 returns the number of digits needed to print v in base b.
+// The base must have been checked.
+func digitsUint64(v uint64, b uint) int {
 	if v == 0 {
 		return 1
 	}
 
-	var d int
-	if v < 0 {
+	// there is no table for larger bases so count the digits by division
+	if b > maxAlphabetBase {
+		d := 1
+		for ; v >= uint64(b); v /= uint64(b) {
+			d++
+		}
+
+		return d
+	}
+
+	dt := digitTables[b]
+
+	// the estimate can be at most two less than the true number of digits
+	d := int((uint64(bits.Len64(v)-1)*dt.mult)>>log2Shift) + 1
+	for d < len(dt.powers) && v >= dt.powers[d] {
 		d++
-		v *= -1
 	}
 
-	logConv := 1.0
-	if b != base10 {
-		logConv = math.Log10(float64(b))
+	return d
+}
+
+// magnitude returns the absolute value of v as a uint64 and true if v is
+// negative. It is correct for the most negative value of each type.
+func magnitude[T constraints.Integer](v T) (uint64, bool) {
+	if v < 0 {
+		return uint64(-(int64(v) + 1)) + 1, true
 	}
 
-	d += int(math.Ceil(math.Log10(float64(v+1)) / logConv))
+	return uint64(v), false
+}
+
+// Digits returns the characters needed to print the value (the number of
+// digits plus potentially a sign marker)
+func Digits[T constraints.Signed](v T) int {
+	return DigitsInBase(v, base10)
+}
+
+// DigitsUnsigned returns the characters needed to print the value
+func DigitsUnsigned[T constraints.Unsigned](v T) int {
+	return DigitsInBaseUnsigned(v, base10)
+}
+
+// DigitsInBase returns the characters needed to print the value v in base
+// b. Note that the base must be 2 or more; if not a panic is generated.
+//
+// The count is calculated exactly using integer arithmetic so it is correct
+// for every value, including the most negative.
+func DigitsInBase[T constraints.Signed](v T, b uint) int {
+	checkMinBase(b)
+
+	mag, neg := magnitude(v)
+
+	d := digitsUint64(mag, b)
+	if neg {
+		d++
+	}
 
 	return d
 }
@@ -55,19 +150,48 @@ func DigitsInBase[T constraints.Signed](v T, b uint) int {
 // (of an unsigned integer type) in base b. Note that the base must be 2 or
 // more; if not a panic is generated.
 func DigitsInBaseUnsigned[T constraints.Unsigned](v T, b uint) int {
-	if b < minBase {
-		panic(fmt.Sprintf("Invalid base (%d), the base must be at least %d",
-			b, minBase))
+	checkMinBase(b)
+
+	return digitsUint64(uint64(v), b)
+}
+
+// DigitsBigInt returns the characters needed to print the value v in base b
+// (the number of digits plus potentially a sign marker), as given by
+// v.Text(b) for the bases that it supports. Note that the base must be 2 or
+// more; if not a panic is generated.
+//
+// The count is calculated without formatting the value so it is suitable
+// for very large values.
+func DigitsBigInt(v *big.Int, b uint) int {
+	checkMinBase(b)
+
+	d := 0
+	if v.Sign() < 0 {
+		d++
 	}
 
-	if v == 0 {
-		return 1
+	if v.IsUint64() || (v.Sign() < 0 && v.BitLen() <= 64) {
+		var mag big.Int
+
+		return d + digitsUint64(mag.Abs(v).Uint64(), b)
 	}
 
-	logConv := 1.0
-	if b != base10 {
-		logConv = math.Log10(float64(b))
+	// est is a lower bound on the number of digits less one (that is, on
+	// the largest power of b no greater than the value). It is reduced by
+	// one to allow for rounding errors.
+	est := int64(float64(v.BitLen()-1)/math.Log2(float64(b))) - 1
+	est = max(est, 0)
+
+	var mag, p, bigB big.Int
+
+	mag.Abs(v)
+	bigB.SetUint64(uint64(b))
+	p.Exp(&bigB, big.NewInt(est), nil)
+
+	d += int(est) + 1
+	for p.Mul(&p, &bigB).Cmp(&mag) <= 0 {
+		d++
 	}
 
-	return int(math.Ceil(math.Log10(float64(v+1)) / logConv))
+	return d
 }
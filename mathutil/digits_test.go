@@ -1,6 +1,11 @@
 package mathutil_test
 
 import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
 	"testing"
 
 	"github.com/nickwells/mathutil.mod/v2/mathutil"
@@ -155,3 +160,163 @@ func TestDigitsInBase(t *testing.T) {
 		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
 	}
 }
+
+func TestDigitsExtremes(t *testing.T) {
+	testhelper.DiffInt(t, "MaxUint64", "digits",
+		mathutil.DigitsUnsigned(uint64(math.MaxUint64)), 20)
+	testhelper.DiffInt(t, "MaxUint64", "binary digits",
+		mathutil.DigitsInBaseUnsigned(uint64(math.MaxUint64), 2), 64)
+	testhelper.DiffInt(t, "MaxUint64", "hex digits",
+		mathutil.DigitsInBaseUnsigned(uint64(math.MaxUint64), 16), 16)
+	testhelper.DiffInt(t, "MaxUint64", "base 36 digits",
+		mathutil.DigitsInBaseUnsigned(uint64(math.MaxUint64), 36), 13)
+	testhelper.DiffInt(t, "10^19-1", "digits",
+		mathutil.DigitsUnsigned(uint64(9_999_999_999_999_999_999)), 19)
+	testhelper.DiffInt(t, "10^19", "digits",
+		mathutil.DigitsUnsigned(uint64(10_000_000_000_000_000_000)), 20)
+	testhelper.DiffInt(t, "MaxInt64", "digits",
+		mathutil.Digits(int64(math.MaxInt64)), 19)
+	testhelper.DiffInt(t, "MinInt64", "digits",
+		mathutil.Digits(int64(math.MinInt64)), 20)
+	testhelper.DiffInt(t, "MinInt8", "digits",
+		mathutil.Digits(int8(math.MinInt8)), 4)
+	testhelper.DiffInt(t, "MinInt8", "binary digits",
+		mathutil.DigitsInBase(int8(math.MinInt8), 2), 9)
+}
+
+// TestDigitsAllBases checks the digit counts against the length of the
+// formatted value for values around each power of each base
+func TestDigitsAllBases(t *testing.T) {
+	for b := uint(2); b <= 36; b++ {
+		for p := uint64(1); p != 0; {
+			for _, v := range []uint64{p - 1, p, p + 1} {
+				exp := len(strconv.FormatUint(v, int(b)))
+				testhelper.DiffInt(t, fmt.Sprintf("v: %d, base %d", v, b),
+					"digits", mathutil.DigitsInBaseUnsigned(v, b), exp)
+
+				iv := -int64(v >> 1)
+				exp = len(strconv.FormatInt(iv, int(b)))
+				testhelper.DiffInt(t, fmt.Sprintf("v: %d, base %d", iv, b),
+					"digits", mathutil.DigitsInBase(iv, b), exp)
+			}
+
+			hi, lo := bits.Mul64(p, uint64(b))
+			if hi != 0 {
+				break
+			}
+
+			p = lo
+		}
+	}
+}
+
+func TestDigitsBadBase(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		base uint
+	}{
+		{
+			ID: testhelper.MkID("bad base (1)"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid base (1), the base must be at least 2"),
+			base: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			mathutil.DigitsInBase(1, tc.base)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+
+		panicked, panicVal = testhelper.PanicSafe(func() {
+			mathutil.DigitsInBaseUnsigned(uint(1), tc.base)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+
+		panicked, panicVal = testhelper.PanicSafe(func() {
+			mathutil.DigitsBigInt(big.NewInt(1), tc.base)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+// refDigits returns the number of digits in v in base b found by repeated
+// division
+func refDigits(v *big.Int, b uint) int {
+	var mag big.Int
+
+	d := 1
+	if v.Sign() < 0 {
+		d++
+	}
+
+	bigB := new(big.Int).SetUint64(uint64(b))
+	for mag.Abs(v); mag.Cmp(bigB) >= 0; mag.Quo(&mag, bigB) {
+		d++
+	}
+
+	return d
+}
+
+func TestDigitsLargeBase(t *testing.T) {
+	bases := []uint{37, 62, 64, 65, 100, 1000, 1 << 32, 1 << 40, math.MaxUint}
+	vals := []int64{
+		0, 1, -1, 36, 37, 63, 64, 65, 99, 100, 999, 1000, 1 << 32, 1 << 40,
+		math.MaxInt64, math.MinInt64,
+	}
+
+	for _, b := range bases {
+		for _, v := range vals {
+			bv := big.NewInt(v)
+			exp := refDigits(bv, b)
+			id := fmt.Sprintf("base: %d, val: %d", b, v)
+
+			testhelper.DiffInt(t, id, "digits",
+				mathutil.DigitsInBase(v, b), exp)
+			testhelper.DiffInt(t, id, "big digits",
+				mathutil.DigitsBigInt(bv, b), exp)
+
+			if v >= 0 {
+				testhelper.DiffInt(t, id, "unsigned digits",
+					mathutil.DigitsInBaseUnsigned(uint64(v), b), exp)
+			}
+		}
+
+		bv := new(big.Int).Lsh(big.NewInt(1), 200)
+		testhelper.DiffInt(t, fmt.Sprintf("base: %d, val: 2^200", b),
+			"big digits", mathutil.DigitsBigInt(bv, b), refDigits(bv, b))
+	}
+}
+
+func TestDigitsBigInt(t *testing.T) {
+	vals := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(-1),
+		big.NewInt(math.MinInt64),
+		new(big.Int).SetUint64(math.MaxUint64),
+	}
+
+	for _, exp := range []int64{64, 65, 100, 1000, 5000} {
+		p := new(big.Int).Lsh(big.NewInt(1), uint(exp))
+		vals = append(vals,
+			p,
+			new(big.Int).Sub(p, big.NewInt(1)),
+			new(big.Int).Neg(p))
+	}
+
+	for _, e := range []int64{20, 100, 1000} {
+		p := new(big.Int).Exp(big.NewInt(10), big.NewInt(e), nil)
+		vals = append(vals, p, new(big.Int).Sub(p, big.NewInt(1)))
+	}
+
+	for _, v := range vals {
+		for b := uint(2); b <= 36; b++ {
+			exp := len(v.Text(int(b)))
+			testhelper.DiffInt(t, fmt.Sprintf("v: %s, base %d", v, b),
+				"digits", mathutil.DigitsBigInt(v, b), exp)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package mathutil
+
+import "golang.org/x/exp/constraints"
+
+// FmtWidthForInts returns the width needed to display all the values in
+// base b as FormatInBase would show them with the same options. It allows
+// for the minus sign, any prefix (see BasePrefix), zero padding and group
+// separators (so BaseGroup(3, ",") gives the width with thousands
+// separators). A width set with BaseWidth is taken as the minimum width.
+// The precision is always zero; it is returned so that the results can be
+// used in the same way as those of FmtValsForSigFigsMulti and combined with
+// them using FmtValsCombine. The base must be at least 2 and no more than
+// the number of digits in the alphabet; if not a panic is generated.
+//
+// If there are no values the width is that of a zero value.
+func FmtWidthForInts[T constraints.Integer](
+	b uint, vals []T, opts ...BaseOpt,
+) (
+	width, precision int,
+) {
+	bc := mkBaseCfg(b, opts)
+
+	width = max(bc.fmtWidth(0, false, maxMagnitude[T](), b), bc.width)
+
+	for _, v := range vals {
+		mag, neg := magnitude(v)
+		width = max(width, bc.fmtWidth(mag, neg, maxMagnitude[T](), b))
+	}
+
+	return width, 0
+}
+
+// fmtWidth returns the number of characters that FormatInBase would use to
+// show the value with the given magnitude and sign, ignoring any padding to
+// a minimum width.
+func (bc baseCfg) fmtWidth(mag uint64, neg bool, maxMag uint64, b uint) int {
+	n := bc.nDigits(mag, maxMag, b)
+
+	width := n + len(bc.prefixFor(b))
+	if neg {
+		width++ // for the minus sign
+	}
+
+	if bc.groupSize > 0 {
+		width += (n - 1) / bc.groupSize * len(bc.groupSep)
+	}
+
+	return width
+}
+
+// FmtValsCombine returns the width and precision needed to display values
+// in a single column where the two widths and precisions are each suitable
+// for a subset of the values. For instance, combining the results of
+// FmtWidthForInts and FmtValsForSigFigsMulti gives a width and precision
+// suitable for a column showing both the integers and the floating point
+// values (with the integers shown as floating point values).
+func FmtValsCombine(width1, precision1, width2, precision2 int) (
+	width, precision int,
+) {
+	precision = max(precision1, precision2)
+
+	width = max(digitsBeforePoint(width1, precision1),
+		digitsBeforePoint(width2, precision2))
+
+	width += precision
+	if precision > 0 {
+		width++ // for the "."
+	}
+
+	return width, precision
+}
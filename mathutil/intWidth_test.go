@@ -0,0 +1,139 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFmtWidthForInts(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		vals     []int64
+		base     uint
+		opts     []mathutil.BaseOpt
+		expWidth int
+	}{
+		{
+			ID:       testhelper.MkID("no values"),
+			base:     10,
+			expWidth: 1,
+		},
+		{
+			ID:       testhelper.MkID("decimal"),
+			vals:     []int64{1, 99, 12345},
+			base:     10,
+			expWidth: 5,
+		},
+		{
+			ID:       testhelper.MkID("negative is widest"),
+			vals:     []int64{-9999, 12345},
+			base:     10,
+			expWidth: 5,
+		},
+		{
+			ID:       testhelper.MkID("negative is wider"),
+			vals:     []int64{-99999, 12345},
+			base:     10,
+			expWidth: 6,
+		},
+		{
+			ID:       testhelper.MkID("thousands"),
+			vals:     []int64{-1234567, 999},
+			base:     10,
+			opts:     []mathutil.BaseOpt{mathutil.BaseGroup(3, ",")},
+			expWidth: 10,
+		},
+		{
+			ID:       testhelper.MkID("hex with prefix"),
+			vals:     []int64{-255, 0x1000},
+			base:     16,
+			opts:     []mathutil.BaseOpt{mathutil.BasePrefix()},
+			expWidth: 6,
+		},
+		{
+			ID:   testhelper.MkID("binary, prefix, full width, nibbles"),
+			vals: []int64{1},
+			base: 2,
+			opts: []mathutil.BaseOpt{
+				mathutil.BasePrefix(),
+				mathutil.BaseFullWidth(),
+				mathutil.BaseGroup(4, "_"),
+			},
+			expWidth: 2 + 64 + 15,
+		},
+		{
+			ID:       testhelper.MkID("prefix ignored for base 10"),
+			vals:     []int64{123},
+			base:     10,
+			opts:     []mathutil.BaseOpt{mathutil.BasePrefix()},
+			expWidth: 3,
+		},
+		{
+			ID:       testhelper.MkID("minimum width"),
+			vals:     []int64{1},
+			base:     10,
+			opts:     []mathutil.BaseOpt{mathutil.BaseWidth(4)},
+			expWidth: 4,
+		},
+		{
+			ID:       testhelper.MkID("MinInt64"),
+			vals:     []int64{math.MinInt64},
+			base:     10,
+			expWidth: 20,
+		},
+	}
+
+	for _, tc := range testCases {
+		width, prec := mathutil.FmtWidthForInts(tc.base, tc.vals, tc.opts...)
+		testhelper.DiffInt(t, tc.IDStr(), "width", width, tc.expWidth)
+		testhelper.DiffInt(t, tc.IDStr(), "precision", prec, 0)
+
+		for _, v := range tc.vals {
+			s := mathutil.FormatInBase(v, tc.base, tc.opts...)
+			if len(s) > width {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: %q is wider than %d", s, width)
+			}
+		}
+	}
+
+	width, _ := mathutil.FmtWidthForInts(10, []uint8{7, 255})
+	testhelper.DiffInt(t, "uint8", "width", width, 3)
+}
+
+func TestBasePrefix(t *testing.T) {
+	pfx := mathutil.BasePrefix()
+
+	testhelper.DiffString(t, "BasePrefix", "hex",
+		mathutil.FormatInBase(-255, 16, pfx), "-0xff")
+	testhelper.DiffString(t, "BasePrefix", "octal",
+		mathutil.FormatInBase(8, 8, pfx), "0o10")
+	testhelper.DiffString(t, "BasePrefix", "binary",
+		mathutil.FormatInBase(5, 2, pfx), "0b101")
+
+	for _, s := range []string{"0xff", "0XFF", "ff"} {
+		v, err := mathutil.ParseInBase[int](s, 16, pfx)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %v", s, err)
+		}
+
+		testhelper.DiffInt(t, "BasePrefix", "parsed "+s, v, 255)
+	}
+
+	_, err := mathutil.ParseInBase[int]("0x", 16, pfx)
+	if err == nil {
+		t.Error("BasePrefix: prefix only: an error was expected")
+	}
+}
+
+func TestFmtValsCombine(t *testing.T) {
+	iw, ip := mathutil.FmtWidthForInts(10, []int{-12345})
+	fw, fp := mathutil.FmtValsForSigFigsMulti(3, 0.5, 1.25)
+
+	width, prec := mathutil.FmtValsCombine(iw, ip, fw, fp)
+	testhelper.DiffInt(t, "FmtValsCombine", "width", width, 10)
+	testhelper.DiffInt(t, "FmtValsCombine", "precision", prec, 3)
+}
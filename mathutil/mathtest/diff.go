@@ -0,0 +1,191 @@
+package mathtest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"golang.org/x/exp/constraints"
+)
+
+// MaxReportedDiffs is the maximum number of differences that will be
+// reported by DiffFloatSlice.
+const MaxReportedDiffs = 10
+
+// maxSigFigs returns the greatest number of significant figures needed to
+// distinguish values of the float type
+func maxSigFigs[F constraints.Float]() uint8 {
+	const (
+		float32SigFigs = 9
+		float64SigFigs = 17
+	)
+
+	if mathutil.BitsOf[F]() == 32 { //nolint:mnd
+		return float32SigFigs
+	}
+
+	return float64SigFigs
+}
+
+// sigFigsToDiffer returns the smallest number of significant figures at
+// which the two values differ. It returns zero if they cannot be
+// distinguished.
+func sigFigsToDiffer[F constraints.Float](a, b F) uint8 {
+	for sf := uint8(1); sf <= maxSigFigs[F](); sf++ {
+		if mathutil.RoundSigFigs(a, sf, mathutil.RoundHalfEven) !=
+			mathutil.RoundSigFigs(b, sf, mathutil.RoundHalfEven) {
+			return sf
+		}
+	}
+
+	return 0
+}
+
+// FmtFloatPair returns the two values formatted to a common width with just
+// enough significant figures to show where they differ. Values which are
+// not finite or which cannot be distinguished are formatted with "%g".
+func FmtFloatPair[F constraints.Float](a, b F) (string, string) {
+	sf := sigFigsToDiffer(a, b)
+
+	if sf == 0 ||
+		math.IsInf(float64(a), 0) || math.IsNaN(float64(a)) ||
+		math.IsInf(float64(b), 0) || math.IsNaN(float64(b)) {
+		sa, sb := fmt.Sprintf("%g", a), fmt.Sprintf("%g", b)
+		w := max(len(sa), len(sb))
+
+		return fmt.Sprintf("%*s", w, sa), fmt.Sprintf("%*s", w, sb)
+	}
+
+	w, p := mathutil.FmtValsForSigFigsMulti(sf, a, b)
+
+	bits := mathutil.BitsOf[F]()
+
+	sa := strconv.FormatFloat(float64(a), 'f', p, bits)
+	sb := strconv.FormatFloat(float64(b), 'f', p, bits)
+
+	// FmtValsForSigFigsMulti gives a limited precision so very small
+	// values may be formatted identically; show them in exponent form. The
+	// formatting rounds the binary value rather than the shortest decimal
+	// form so more digits may be needed to tell them apart.
+	for prec := int(sf) - 1; sa == sb && prec < int(maxSigFigs[F]()); prec++ {
+		sa = strconv.FormatFloat(float64(a), 'e', prec, bits)
+		sb = strconv.FormatFloat(float64(b), 'e', prec, bits)
+		w = max(len(sa), len(sb))
+	}
+
+	return fmt.Sprintf("%*s", w, sa), fmt.Sprintf("%*s", w, sb)
+}
+
+// diffMarker returns a string with a '^' under each character which
+// differs between the two strings.
+func diffMarker(a, b string) string {
+	var m strings.Builder
+
+	for i := range max(len(a), len(b)) {
+		if i < len(a) && i < len(b) && a[i] == b[i] {
+			m.WriteByte(' ')
+		} else {
+			m.WriteByte('^')
+		}
+	}
+
+	return strings.TrimRight(m.String(), " ")
+}
+
+// reportFloatDiff reports the difference between two float values
+func reportFloatDiff[F constraints.Float](t testing.TB, name string,
+	act, exp F,
+) {
+	t.Helper()
+
+	sAct, sExp := FmtFloatPair(act, exp)
+	charCnt := len(name) + len("expected") + 1
+
+	t.Logf("\t: expected %s: %s\n", name, sExp)
+	t.Logf("\t:   actual %s: %s\n", name, sAct)
+	t.Logf("\t: %*s  %s\n", charCnt, "", diffMarker(sAct, sExp))
+	t.Logf("\t: %*s: %g\n", charCnt, "diff", act-exp)
+	t.Errorf("\t: %s is incorrect\n", name)
+}
+
+// DiffFloat compares the actual against the expected value and reports an
+// error if they are not close enough according to the tolerance.
+//
+// It returns true if the actual and expected values differ, false otherwise.
+func DiffFloat[F constraints.Float](t testing.TB, id, name string,
+	act, exp F, tol Tolerance[F],
+) bool {
+	t.Helper()
+
+	if !tol(act, exp) {
+		t.Log(id)
+		reportFloatDiff(t, name, act, exp)
+
+		return true
+	}
+
+	return false
+}
+
+// DiffRational compares the actual against the expected Rational value and
+// reports an error if the values they represent are not close enough
+// according to the tolerance.
+//
+// It returns true if the actual and expected values differ, false otherwise.
+func DiffRational(t testing.TB, id, name string,
+	act, exp mathutil.Rational, tol Tolerance[float64],
+) bool {
+	t.Helper()
+
+	actF, expF := act.AsFloat64(), exp.AsFloat64()
+
+	if !tol(actF, expF) {
+		t.Log(id)
+		t.Logf("\t: expected %s: %d/%d\n", name, exp.N, exp.D)
+		t.Logf("\t:   actual %s: %d/%d\n", name, act.N, act.D)
+		reportFloatDiff(t, name+" value", actF, expF)
+
+		return true
+	}
+
+	return false
+}
+
+// DiffFloatSlice compares the actual against the expected slice and
+// reports an error if they differ in length or if any of the values are
+// not close enough according to the tolerance. At most MaxReportedDiffs
+// differences are shown.
+//
+// It returns true if the actual and expected slices differ, false
+// otherwise.
+func DiffFloatSlice[F constraints.Float](t testing.TB, id, name string,
+	act, exp []F, tol Tolerance[F],
+) bool {
+	t.Helper()
+
+	eq, d := mathutil.SlicesAlmostEqual(act, exp, tol, MaxReportedDiffs)
+	if eq {
+		return false
+	}
+
+	t.Log(id)
+
+	if len(act) != len(exp) {
+		t.Logf("\t: expected %s length: %d\n", name, len(exp))
+		t.Logf("\t:   actual %s length: %d\n", name, len(act))
+		t.Errorf("\t: %s is incorrect\n", name)
+	}
+
+	for _, m := range d.Mismatches {
+		reportFloatDiff(t, fmt.Sprintf("%s [%d]", name, m.Key), m.A, m.B)
+	}
+
+	if hidden := d.Count - len(d.Mismatches); hidden > 0 {
+		t.Logf("\t: ... %d more differences not shown\n", hidden)
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+package mathtest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/mathutil.mod/v2/mathutil/mathtest"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// recordingTB records the messages logged and whether an error was
+// reported.
+type recordingTB struct {
+	testing.TB
+	msgs   []string
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Log(args ...any) {
+	r.msgs = append(r.msgs, fmt.Sprint(args...))
+}
+
+func (r *recordingTB) Logf(format string, args ...any) {
+	r.msgs = append(r.msgs,
+		strings.TrimRight(fmt.Sprintf(format, args...), "\n"))
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failed = true
+	r.Logf(format, args...)
+}
+
+func TestFmtFloatPair(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		a, b float64
+		expA string
+		expB string
+	}{
+		{
+			ID:   testhelper.MkID("differ in the 6th digit"),
+			a:    1.234561,
+			b:    1.234571,
+			expA: "1.23456",
+			expB: "1.23457",
+		},
+		{
+			ID:   testhelper.MkID("differ in the 1st digit"),
+			a:    100,
+			b:    200,
+			expA: "100",
+			expB: "200",
+		},
+		{
+			ID:   testhelper.MkID("differing magnitudes"),
+			a:    12.5,
+			b:    0.125,
+			expA: "12.5",
+			expB: " 0.1",
+		},
+		{
+			ID:   testhelper.MkID("below 1e-9"),
+			a:    1e-12,
+			b:    1.1e-12,
+			expA: "1.0e-12",
+			expB: "1.1e-12",
+		},
+		{
+			ID:   testhelper.MkID("just below 1e-9"),
+			a:    2.5e-10,
+			b:    2.6e-10,
+			expA: "2.5e-10",
+			expB: "2.6e-10",
+		},
+		{
+			ID:   testhelper.MkID("identical"),
+			a:    1.5,
+			b:    1.5,
+			expA: "1.5",
+			expB: "1.5",
+		},
+	}
+
+	for _, tc := range testCases {
+		sa, sb := mathtest.FmtFloatPair(tc.a, tc.b)
+		testhelper.DiffString(t, tc.IDStr(), "a", sa, tc.expA)
+		testhelper.DiffString(t, tc.IDStr(), "b", sb, tc.expB)
+	}
+}
+
+func TestDiffFloat(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		act, exp  float64
+		tol       mathtest.Tolerance[float64]
+		expDiff   bool
+		expReport []string
+	}{
+		{
+			ID:  testhelper.MkID("close enough"),
+			act: 1.0,
+			exp: 1.0000001,
+			tol: mathtest.Abs(1e-6),
+		},
+		{
+			ID:      testhelper.MkID("too far apart"),
+			act:     1.234561,
+			exp:     1.234571,
+			tol:     mathtest.Ulps[float64](4),
+			expDiff: true,
+			expReport: []string{
+				"id",
+				"\t: expected val: 1.23457",
+				"\t:   actual val: 1.23456",
+				"\t:                     ^",
+			},
+		},
+		{
+			ID:      testhelper.MkID("too far apart, below 1e-9"),
+			act:     2.5e-10,
+			exp:     2.6e-10,
+			tol:     mathtest.Ulps[float64](4),
+			expDiff: true,
+			expReport: []string{
+				"id",
+				"\t: expected val: 2.6e-10",
+				"\t:   actual val: 2.5e-10",
+				"\t:                 ^",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		rec := &recordingTB{}
+		diff := mathtest.DiffFloat(rec, "id", "val", tc.act, tc.exp, tc.tol)
+		testhelper.DiffBool(t, tc.IDStr(), "differs", diff, tc.expDiff)
+		testhelper.DiffBool(t, tc.IDStr(), "failed", rec.failed, tc.expDiff)
+
+		if len(rec.msgs) >= len(tc.expReport) {
+			testhelper.DiffStringSlice(t, tc.IDStr(), "report",
+				rec.msgs[:len(tc.expReport)], tc.expReport)
+		} else {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: report too short: %q", rec.msgs)
+		}
+	}
+}
+
+func TestDiffRational(t *testing.T) {
+	rec := &recordingTB{}
+	diff := mathtest.DiffRational(rec, "id", "r",
+		mathutil.Rational{N: 1, D: 3}, mathutil.Rational{N: 2, D: 6},
+		mathtest.Exact[float64]())
+	testhelper.DiffBool(t, "equal rationals", "differs", diff, false)
+
+	diff = mathtest.DiffRational(rec, "id", "r",
+		mathutil.Rational{N: 1, D: 3}, mathutil.Rational{N: 1, D: 2},
+		mathtest.Pct(1.0))
+	testhelper.DiffBool(t, "unequal rationals", "differs", diff, true)
+	testhelper.DiffBool(t, "unequal rationals", "failed", rec.failed, true)
+}
+
+func TestDiffFloatSlice(t *testing.T) {
+	rec := &recordingTB{}
+	diff := mathtest.DiffFloatSlice(rec, "id", "s",
+		[]float32{1, 2, 3}, []float32{1, 2, 3},
+		mathtest.Close[float32]())
+	testhelper.DiffBool(t, "equal slices", "differs", diff, false)
+
+	diff = mathtest.DiffFloatSlice(rec, "id", "s",
+		[]float32{1, 2, 3}, []float32{1, 2.5},
+		mathtest.Close[float32]())
+	testhelper.DiffBool(t, "unequal slices", "differs", diff, true)
+	testhelper.DiffBool(t, "unequal slices", "failed", rec.failed, true)
+	testhelper.DiffStringSlice(t, "unequal slices", "report", rec.msgs[:3],
+		[]string{
+			"id",
+			"\t: expected s length: 2",
+			"\t:   actual s length: 3",
+		})
+}
@@ -0,0 +1,8 @@
+/*
+Package mathtest offers helper functions for tests which compare floating
+point values. They are similar to the Diff... functions in the
+github.com/nickwells/testhelper.mod/v2/testhelper package but take a
+tolerance policy and report any mismatch showing just enough significant
+figures to reveal the digits that differ.
+*/
+package mathtest
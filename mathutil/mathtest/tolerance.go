@@ -0,0 +1,49 @@
+package mathtest
+
+import (
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"golang.org/x/exp/constraints"
+)
+
+// Tolerance is a policy deciding whether an actual value is close enough
+// to the expected value.
+type Tolerance[F constraints.Float] func(act, exp F) bool
+
+// Exact returns a Tolerance which requires the values to be identical
+func Exact[F constraints.Float]() Tolerance[F] {
+	return func(act, exp F) bool {
+		return act == exp
+	}
+}
+
+// Abs returns a Tolerance which requires the values to be within epsilon of
+// one another. See mathutil.AlmostEqual.
+func Abs[F constraints.Float](epsilon F) Tolerance[F] {
+	return func(act, exp F) bool {
+		return mathutil.AlmostEqual(float64(act), float64(exp),
+			float64(epsilon))
+	}
+}
+
+// Pct returns a Tolerance which requires the values to be within pct
+// percent of one another. See mathutil.WithinNPercent.
+func Pct[F constraints.Float](pct F) Tolerance[F] {
+	return func(act, exp F) bool {
+		return mathutil.WithinNPercent(float64(act), float64(exp),
+			float64(pct))
+	}
+}
+
+// Ulps returns a Tolerance which requires the values to be no more than
+// maxUlps units in the last place apart. See mathutil.AlmostEqualUlps.
+func Ulps[F constraints.Float](maxUlps uint64) Tolerance[F] {
+	return func(act, exp F) bool {
+		return mathutil.AlmostEqualUlps(act, exp, maxUlps)
+	}
+}
+
+// Close returns a Tolerance which requires the values to pass the
+// mathutil.IsClose test with the given options.
+func Close[F constraints.Float](opts ...mathutil.IsCloseOpt) Tolerance[F] {
+	return Tolerance[F](mathutil.IsCloseFunc[F](opts...))
+}
@@ -1,51 +1,277 @@
 package mathutil
 
 import (
-	"math"
+	"cmp"
+	"fmt"
 
 	"golang.org/x/exp/constraints"
 )
 
 // MinOf returns the lesser of the slice of values; it will panic if the
-// slice is empty
-func MinOf[F constraints.Float](vals ...F) F {
-	m := float64(vals[0])
+// slice is empty. If any of the values is a NaN the result is NaN; see
+// MinOfOK for a version which does not panic and gives control over the
+// treatment of NaNs.
+func MinOf[T cmp.Ordered](vals ...T) T {
+	m := vals[0]
 	for _, v := range vals[1:] {
-		m = math.Min(m, float64(v))
+		m = min(m, v)
 	}
 
-	return F(m)
+	return m
 }
 
 // MaxOf returns the greater of the slice of values; it will panic if the
-// slice is empty
-func MaxOf[F constraints.Float](vals ...F) F {
-	m := float64(vals[0])
+// slice is empty. If any of the values is a NaN the result is NaN; see
+// MaxOfOK for a version which does not panic and gives control over the
+// treatment of NaNs.
+func MaxOf[T cmp.Ordered](vals ...T) T {
+	m := vals[0]
 	for _, v := range vals[1:] {
-		m = math.Max(m, float64(v))
+		m = max(m, v)
 	}
 
-	return F(m)
+	return m
 }
 
 // MinOfInt returns the lesser of the slice of values; it will panic if the
 // slice is empty
+//
+// Deprecated: use MinOf which now accepts integer values
 func MinOfInt[I constraints.Integer](vals ...I) I {
-	m := vals[0]
-	for _, v := range vals[1:] {
-		m = min(v, m)
-	}
-
-	return m
+	return MinOf(vals...)
 }
 
 // MaxOfInt returns the greater of the slice of values; it will panic if the
 // slice is empty
+//
+// Deprecated: use MaxOf which now accepts integer values
 func MaxOfInt[I constraints.Integer](vals ...I) I {
-	m := vals[0]
-	for _, v := range vals[1:] {
-		m = max(v, m)
+	return MaxOf(vals...)
+}
+
+// NaNPolicy determines how NaN values are treated when finding the least
+// or greatest of a set of values
+type NaNPolicy int
+
+// These are the available NaNPolicy values
+const (
+	// NaNPropagate makes any NaN the result, as for MinOf and MaxOf
+	NaNPropagate NaNPolicy = iota
+	// NaNIgnore skips any NaN values
+	NaNIgnore
+	// NaNLowest treats a NaN as less than any other value
+	NaNLowest
+	// NaNHighest treats a NaN as greater than any other value
+	NaNHighest
+)
+
+// String returns a string value for the NaNPolicy
+func (p NaNPolicy) String() string {
+	switch p {
+	case NaNPropagate:
+		return "NaNPropagate"
+	case NaNIgnore:
+		return "NaNIgnore"
+	case NaNLowest:
+		return "NaNLowest"
+	case NaNHighest:
+		return "NaNHighest"
 	}
 
-	return m
+	return fmt.Sprintf("NaNPolicy(%d)", int(p))
+}
+
+// nanWins reports whether a NaN should be chosen in preference to any
+// other value when looking for the least (or greatest if wantMax is true)
+// value. It will panic if the policy is not known.
+func (p NaNPolicy) nanWins(wantMax bool) bool {
+	switch p {
+	case NaNPropagate:
+		return true
+	case NaNIgnore:
+		return false
+	case NaNLowest:
+		return !wantMax
+	case NaNHighest:
+		return wantMax
+	}
+
+	panic(fmt.Sprintf("Invalid NaN policy: %s", p))
+}
+
+// extremeOf returns the least (or greatest if wantMax is true) of the
+// values treating NaNs according to the policy. It returns false if there
+// is no such value.
+func extremeOf[T cmp.Ordered](p NaNPolicy, wantMax bool, vals []T) (T, bool) {
+	var (
+		m        T
+		found    bool
+		nan      T
+		foundNaN bool
+	)
+
+	nanWins := p.nanWins(wantMax)
+
+	for _, v := range vals {
+		switch {
+		case isNaN(v):
+			if nanWins {
+				return v, true
+			}
+
+			nan, foundNaN = v, true
+		case !found:
+			m, found = v, true
+		case wantMax:
+			m = max(m, v)
+		default:
+			m = min(m, v)
+		}
+	}
+
+	if !found && foundNaN && p != NaNIgnore {
+		return nan, true
+	}
+
+	return m, found
+}
+
+// MinOfOK returns the lesser of the values and true. NaN values are treated
+// according to the NaNPolicy. If there are no values (or, with a policy of
+// NaNIgnore, no values other than NaNs) the zero value and false are
+// returned.
+func MinOfOK[T cmp.Ordered](p NaNPolicy, vals ...T) (T, bool) {
+	return extremeOf(p, false, vals)
+}
+
+// MaxOfOK returns the greater of the values and true. NaN values are
+// treated according to the NaNPolicy. If there are no values (or, with a
+// policy of NaNIgnore, no values other than NaNs) the zero value and false
+// are returned.
+func MaxOfOK[T cmp.Ordered](p NaNPolicy, vals ...T) (T, bool) {
+	return extremeOf(p, true, vals)
+}
+
+// TieBreak determines which value is chosen when several are equally
+// extreme
+type TieBreak int
+
+// These are the available TieBreak values
+const (
+	// TieFirst chooses the first of the equal values
+	TieFirst TieBreak = iota
+	// TieLast chooses the last of the equal values
+	TieLast
+)
+
+// isNaN returns true if v is a NaN. It works for any ordered type; only
+// floats can be NaN.
+func isNaN[T cmp.Ordered](v T) bool {
+	return v != v //nolint:gocritic
+}
+
+// argBy returns the index of the entry in vals with the least key (or the
+// greatest key if wantMax is true). Ties are resolved according to tb and a
+// NaN key is preferred to any other value (in keeping with MinOf and MaxOf)
+// so the index of the first NaN is returned. It returns -1 if vals is
+// empty.
+func argBy[E any, K cmp.Ordered](
+	vals []E, key func(E) K, wantMax bool, tb TieBreak,
+) int {
+	if len(vals) == 0 {
+		return -1
+	}
+
+	best, bestKey := 0, key(vals[0])
+	if isNaN(bestKey) {
+		return 0
+	}
+
+	for i, v := range vals[1:] {
+		k := key(v)
+		if isNaN(k) {
+			return i + 1
+		}
+
+		c := cmp.Compare(k, bestKey)
+		if wantMax {
+			c = -c
+		}
+
+		if c < 0 || (c == 0 && tb == TieLast) {
+			best, bestKey = i+1, k
+		}
+	}
+
+	return best
+}
+
+// identity returns its argument
+func identity[T any](v T) T {
+	return v
+}
+
+// ArgMin returns the index of the least of the values; if several values
+// are equally small the tie-break policy determines which index is
+// returned. As with MinOf, a NaN is preferred to any other value so the
+// index of the first NaN is returned. It returns -1 if the slice is empty.
+func ArgMin[T Number](vals []T, tb TieBreak) int {
+	return argBy(vals, identity[T], false, tb)
+}
+
+// ArgMax returns the index of the greatest of the values; if several values
+// are equally large the tie-break policy determines which index is
+// returned. As with MaxOf, a NaN is preferred to any other value so the
+// index of the first NaN is returned. It returns -1 if the slice is empty.
+func ArgMax[T Number](vals []T, tb TieBreak) int {
+	return argBy(vals, identity[T], true, tb)
+}
+
+// MinMax returns both the lesser and the greater of the slice of values
+// using a single pass over the values. As with MinOf and MaxOf, if any of
+// the values is a NaN then both results will be NaN. It will panic if the
+// slice is empty.
+func MinMax[T Number](vals ...T) (minVal, maxVal T) {
+	minVal, maxVal = vals[0], vals[0]
+
+	for _, v := range vals {
+		if isNaN(v) {
+			return v, v
+		}
+
+		minVal = min(minVal, v)
+		maxVal = max(maxVal, v)
+	}
+
+	return minVal, maxVal
+}
+
+// MinBy returns the entry in vals with the least value of the key function
+// together with its index. If several entries have equally small keys the
+// first is returned. A NaN key is preferred to any other value. If vals is
+// empty the zero value and an index of -1 are returned.
+func MinBy[E any, K cmp.Ordered](vals []E, key func(E) K) (E, int) {
+	var e E
+
+	i := argBy(vals, key, false, TieFirst)
+	if i >= 0 {
+		e = vals[i]
+	}
+
+	return e, i
+}
+
+// MaxBy returns the entry in vals with the greatest value of the key
+// function together with its index. If several entries have equally large
+// keys the first is returned. A NaN key is preferred to any other value. If
+// vals is empty the zero value and an index of -1 are returned.
+func MaxBy[E any, K cmp.Ordered](vals []E, key func(E) K) (E, int) {
+	var e E
+
+	i := argBy(vals, key, true, TieFirst)
+	if i >= 0 {
+		e = vals[i]
+	}
+
+	return e, i
 }
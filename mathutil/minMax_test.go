@@ -1,6 +1,7 @@
 package mathutil_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/nickwells/mathutil.mod/v2/mathutil"
@@ -97,6 +98,16 @@ func TestMinMaxOfInt(t *testing.T) {
 		if panicOK(t, tc.IDStr()+": MaxOfInt", panicked, tc.panicExp, panicVal) {
 			testhelper.DiffInt(t, tc.IDStr(), "max", v, tc.expMax)
 		}
+
+		v, panicked, panicVal = panicSafeInt(mathutil.MinOf[int], tc.vals)
+		if panicOK(t, tc.IDStr()+": MinOf", panicked, tc.panicExp, panicVal) {
+			testhelper.DiffInt(t, tc.IDStr(), "min", v, tc.expMin)
+		}
+
+		v, panicked, panicVal = panicSafeInt(mathutil.MaxOf[int], tc.vals)
+		if panicOK(t, tc.IDStr()+": MaxOf", panicked, tc.panicExp, panicVal) {
+			testhelper.DiffInt(t, tc.IDStr(), "max", v, tc.expMax)
+		}
 	}
 }
 
@@ -157,3 +168,257 @@ func panicSafeInt(f func(...int) int, vals []int) (
 
 	return
 }
+
+func TestArgMinMax(t *testing.T) {
+	nan := math.NaN()
+
+	testCases := []struct {
+		testhelper.ID
+		vals      []float64
+		tb        mathutil.TieBreak
+		expArgMin int
+		expArgMax int
+	}{
+		{
+			ID:        testhelper.MkID("empty"),
+			expArgMin: -1,
+			expArgMax: -1,
+		},
+		{
+			ID:        testhelper.MkID("one val"),
+			vals:      []float64{1},
+			expArgMin: 0,
+			expArgMax: 0,
+		},
+		{
+			ID:        testhelper.MkID("multiple vals"),
+			vals:      []float64{2, -1, 3, 0},
+			expArgMin: 1,
+			expArgMax: 2,
+		},
+		{
+			ID:        testhelper.MkID("ties, first"),
+			vals:      []float64{1, 3, 1, 3},
+			tb:        mathutil.TieFirst,
+			expArgMin: 0,
+			expArgMax: 1,
+		},
+		{
+			ID:        testhelper.MkID("ties, last"),
+			vals:      []float64{1, 3, 1, 3},
+			tb:        mathutil.TieLast,
+			expArgMin: 2,
+			expArgMax: 3,
+		},
+		{
+			ID:        testhelper.MkID("NaN"),
+			vals:      []float64{1, nan, 3, nan},
+			tb:        mathutil.TieLast,
+			expArgMin: 1,
+			expArgMax: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffInt(t, tc.IDStr(), "ArgMin",
+			mathutil.ArgMin(tc.vals, tc.tb), tc.expArgMin)
+		testhelper.DiffInt(t, tc.IDStr(), "ArgMax",
+			mathutil.ArgMax(tc.vals, tc.tb), tc.expArgMax)
+	}
+
+	ints := []uint8{4, 2, 9, 2}
+	testhelper.DiffInt(t, "uint8", "ArgMin",
+		mathutil.ArgMin(ints, mathutil.TieLast), 3)
+	testhelper.DiffInt(t, "uint8", "ArgMax",
+		mathutil.ArgMax(ints, mathutil.TieLast), 2)
+}
+
+func TestMinMax(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		vals     []float64
+		expMin   float64
+		expMax   float64
+		expNaN   bool
+		panicExp bool
+	}{
+		{
+			ID:       testhelper.MkID("empty"),
+			panicExp: true,
+		},
+		{
+			ID:     testhelper.MkID("one val"),
+			vals:   []float64{1},
+			expMin: 1,
+			expMax: 1,
+		},
+		{
+			ID:     testhelper.MkID("multiple vals"),
+			vals:   []float64{2, -1, 3, 0},
+			expMin: -1,
+			expMax: 3,
+		},
+		{
+			ID:     testhelper.MkID("NaN"),
+			vals:   []float64{2, math.NaN(), 3},
+			expNaN: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			minVal, maxVal := mathutil.MinMax(tc.vals...)
+			if tc.expNaN {
+				testhelper.DiffBool(t, tc.IDStr(), "min is NaN",
+					math.IsNaN(minVal), true)
+				testhelper.DiffBool(t, tc.IDStr(), "max is NaN",
+					math.IsNaN(maxVal), true)
+
+				return
+			}
+
+			testhelper.DiffFloat(t, tc.IDStr(), "min", minVal, tc.expMin, 0)
+			testhelper.DiffFloat(t, tc.IDStr(), "max", maxVal, tc.expMax, 0)
+		})
+		panicOK(t, tc.IDStr()+": MinMax", panicked, tc.panicExp, panicVal)
+	}
+
+	minVal, maxVal := mathutil.MinMax(3, 1, 2)
+	testhelper.DiffInt(t, "ints", "min", minVal, 1)
+	testhelper.DiffInt(t, "ints", "max", maxVal, 3)
+}
+
+func TestMinMaxBy(t *testing.T) {
+	type person struct {
+		name string
+		age  int
+	}
+
+	people := []person{
+		{name: "Ann", age: 42},
+		{name: "Bob", age: 17},
+		{name: "Cat", age: 63},
+		{name: "Dan", age: 17},
+	}
+	age := func(p person) int { return p.age }
+
+	p, i := mathutil.MinBy(people, age)
+	testhelper.DiffString(t, "MinBy", "name", p.name, "Bob")
+	testhelper.DiffInt(t, "MinBy", "index", i, 1)
+
+	p, i = mathutil.MaxBy(people, age)
+	testhelper.DiffString(t, "MaxBy", "name", p.name, "Cat")
+	testhelper.DiffInt(t, "MaxBy", "index", i, 2)
+
+	p, i = mathutil.MaxBy([]person{}, age)
+	testhelper.DiffString(t, "MaxBy (empty)", "name", p.name, "")
+	testhelper.DiffInt(t, "MaxBy (empty)", "index", i, -1)
+}
+
+func TestMinMaxOfOK(t *testing.T) {
+	nan := math.NaN()
+
+	testCases := []struct {
+		testhelper.ID
+		vals      []float64
+		policy    mathutil.NaNPolicy
+		expMin    float64
+		expMinOK  bool
+		expMinNaN bool
+		expMax    float64
+		expMaxOK  bool
+		expMaxNaN bool
+	}{
+		{
+			ID: testhelper.MkID("empty"),
+		},
+		{
+			ID:       testhelper.MkID("no NaNs"),
+			vals:     []float64{2, -1, 3},
+			expMin:   -1,
+			expMinOK: true,
+			expMax:   3,
+			expMaxOK: true,
+		},
+		{
+			ID:        testhelper.MkID("NaN, propagate"),
+			vals:      []float64{2, nan, 3},
+			policy:    mathutil.NaNPropagate,
+			expMinOK:  true,
+			expMinNaN: true,
+			expMaxOK:  true,
+			expMaxNaN: true,
+		},
+		{
+			ID:       testhelper.MkID("NaN, ignore"),
+			vals:     []float64{2, nan, 3},
+			policy:   mathutil.NaNIgnore,
+			expMin:   2,
+			expMinOK: true,
+			expMax:   3,
+			expMaxOK: true,
+		},
+		{
+			ID:     testhelper.MkID("all NaN, ignore"),
+			vals:   []float64{nan, nan},
+			policy: mathutil.NaNIgnore,
+		},
+		{
+			ID:        testhelper.MkID("NaN, lowest"),
+			vals:      []float64{2, nan, 3},
+			policy:    mathutil.NaNLowest,
+			expMinOK:  true,
+			expMinNaN: true,
+			expMax:    3,
+			expMaxOK:  true,
+		},
+		{
+			ID:        testhelper.MkID("NaN, highest"),
+			vals:      []float64{2, nan, 3},
+			policy:    mathutil.NaNHighest,
+			expMin:    2,
+			expMinOK:  true,
+			expMaxOK:  true,
+			expMaxNaN: true,
+		},
+		{
+			ID:        testhelper.MkID("all NaN, highest"),
+			vals:      []float64{nan},
+			policy:    mathutil.NaNHighest,
+			expMinOK:  true,
+			expMinNaN: true,
+			expMaxOK:  true,
+			expMaxNaN: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		v, ok := mathutil.MinOfOK(tc.policy, tc.vals...)
+		testhelper.DiffBool(t, tc.IDStr(), "min ok", ok, tc.expMinOK)
+
+		if tc.expMinNaN {
+			testhelper.DiffBool(t, tc.IDStr(), "min is NaN",
+				math.IsNaN(v), true)
+		} else {
+			testhelper.DiffFloat(t, tc.IDStr(), "min", v, tc.expMin, 0)
+		}
+
+		v, ok = mathutil.MaxOfOK(tc.policy, tc.vals...)
+		testhelper.DiffBool(t, tc.IDStr(), "max ok", ok, tc.expMaxOK)
+
+		if tc.expMaxNaN {
+			testhelper.DiffBool(t, tc.IDStr(), "max is NaN",
+				math.IsNaN(v), true)
+		} else {
+			testhelper.DiffFloat(t, tc.IDStr(), "max", v, tc.expMax, 0)
+		}
+	}
+
+	s, ok := mathutil.MinOfOK(mathutil.NaNPropagate, "b", "a", "c")
+	testhelper.DiffString(t, "strings", "min", s, "a")
+	testhelper.DiffBool(t, "strings", "min ok", ok, true)
+
+	i, ok := mathutil.MaxOfOK[int](mathutil.NaNPropagate)
+	testhelper.DiffInt(t, "no ints", "max", i, 0)
+	testhelper.DiffBool(t, "no ints", "max ok", ok, false)
+}
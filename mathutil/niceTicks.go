@@ -0,0 +1,381 @@
+package mathutil
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const minTicks = 2
+
+// Ticks records a set of "nice" values suitable for marking an axis on a
+// chart. Lower and Upper give the range of the axis and will enclose the
+// data range used to generate them. Step is the difference between
+// successive ticks (for a logarithmic axis it is the ratio between them).
+//
+// Precision is the number of digits to show after the decimal point and
+// Width is the number of characters needed to show the widest tick. Together
+// they can be used to print the values (with a format such as "%*.*f") in
+// the same way as the values returned by FmtValsForSigFigs.
+type Ticks struct {
+	Lower     float64
+	Upper     float64
+	Step      float64
+	Vals      []float64
+	Precision int
+	Width     int
+}
+
+// niceStep is a "nice" number; that is, a value of m times a power of 10
+// where m is 1, 2 or 5. It is held in this form so that multiples of it can
+// be calculated exactly.
+type niceStep struct {
+	m   int64
+	exp int
+}
+
+// pow10 returns the float64 value closest to 10 to the power exp. Unlike
+// math.Pow10 this is correctly rounded for every exponent.
+func pow10(exp int) float64 {
+	v, _ := strconv.ParseFloat("1e"+strconv.Itoa(exp), float64Bits)
+	return v
+}
+
+// niceNum returns a niceStep approximately equal to x which must be
+// greater than zero. If round is true the number is rounded to the nearest
+// nice value, otherwise it is the nice value no less than x.
+func niceNum(x float64, round bool) niceStep {
+	// take the decimal mantissa and exponent from the formatted value
+	// rather than by dividing by a power of 10 which may not be exact
+	mantissa, exp, _ := strings.Cut(
+		strconv.FormatFloat(x, 'e', -1, float64Bits), "e")
+	f, _ := strconv.ParseFloat(mantissa, float64Bits)
+	e, _ := strconv.Atoi(exp)
+
+	var nf int64
+
+	if round {
+		switch {
+		case f < 1.5: //nolint:mnd
+			nf = 1
+		case f < 3: //nolint:mnd
+			nf = 2
+		case f < 7: //nolint:mnd
+			nf = 5
+		default:
+			nf = 10
+		}
+	} else {
+		switch {
+		case f <= 1:
+			nf = 1
+		case f <= 2: //nolint:mnd
+			nf = 2
+		case f <= 5: //nolint:mnd
+			nf = 5
+		default:
+			nf = 10
+		}
+	}
+
+	if nf == 10 { //nolint:mnd
+		return niceStep{m: 1, exp: e + 1}
+	}
+
+	return niceStep{m: nf, exp: e}
+}
+
+// next returns the next larger nice number
+func (s niceStep) next() niceStep {
+	switch s.m {
+	case 1:
+		return niceStep{m: 2, exp: s.exp} //nolint:mnd
+	case 2: //nolint:mnd
+		return niceStep{m: 5, exp: s.exp} //nolint:mnd
+	}
+
+	return niceStep{m: 1, exp: s.exp + 1}
+}
+
+// String returns the nice number in exponent form
+func (s niceStep) String() string {
+	return strconv.FormatInt(s.m, 10) + "e" + strconv.Itoa(s.exp) //nolint:mnd
+}
+
+// float returns the float64 value closest to the nice number
+func (s niceStep) float() float64 {
+	v, _ := strconv.ParseFloat(s.String(), float64Bits)
+	return v
+}
+
+// rat returns the exact value of the nice number
+func (s niceStep) rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(s.String())
+	return r
+}
+
+// precision returns the number of digits needed after the decimal point to
+// show multiples of the nice number
+func (s niceStep) precision() int {
+	return max(0, -s.exp)
+}
+
+// tickBounds returns the multipliers of the step giving the ticks which
+// most closely enclose the range.
+func tickBounds(minVal, maxVal float64, s niceStep) (lo, hi *big.Int) {
+	step := s.rat()
+	loR := new(big.Rat).Quo(new(big.Rat).SetFloat64(minVal), step)
+	hiR := new(big.Rat).Quo(new(big.Rat).SetFloat64(maxVal), step)
+
+	// the denominator is always positive so Div rounds towards minus
+	// infinity
+	lo = new(big.Int).Div(loR.Num(), loR.Denom())
+	hi = new(big.Int).Div(hiR.Num(), hiR.Denom())
+
+	if !hiR.IsInt() {
+		hi.Add(hi, big.NewInt(1))
+	}
+
+	return lo, hi
+}
+
+// tickCountFits returns true if the number of ticks from lo to hi is no
+// more than maxTicks
+func tickCountFits(lo, hi *big.Int, maxTicks int) bool {
+	n := new(big.Int).Sub(hi, lo)
+
+	return n.Cmp(big.NewInt(int64(maxTicks-1))) <= 0
+}
+
+// checkTickParams checks the parameters to the tick generating functions,
+// it will panic if they are invalid. It returns the min and max values in
+// order.
+func checkTickParams(minVal, maxVal float64, maxTicks int) (float64, float64) {
+	if maxTicks < minTicks {
+		panic(fmt.Sprintf(
+			"Invalid maximum number of ticks (%d), it must be at least %d",
+			maxTicks, minTicks))
+	}
+
+	if math.IsNaN(minVal) || math.IsInf(minVal, 0) ||
+		math.IsNaN(maxVal) || math.IsInf(maxVal, 0) {
+		panic(fmt.Sprintf(
+			"Invalid tick range [%g, %g], the values must be finite",
+			minVal, maxVal))
+	}
+
+	if minVal > maxVal {
+		minVal, maxVal = maxVal, minVal
+	}
+
+	return minVal, maxVal
+}
+
+// setWidth sets the Width of the Ticks from the values and the Precision
+func (t *Ticks) setWidth() {
+	t.Width = 0
+	for _, v := range t.Vals {
+		t.Width = max(t.Width,
+			len(strconv.FormatFloat(v, 'f', t.Precision, float64Bits)))
+	}
+}
+
+// panicRangeTooLarge panics, reporting that the range is too large for the
+// ticks to be represented
+func panicRangeTooLarge(minVal, maxVal float64) {
+	panic(fmt.Sprintf("Invalid tick range [%g, %g], the range is too large",
+		minVal, maxVal))
+}
+
+// setVals sets the tick values to the multiples of the step from lo to hi,
+// taking every n'th multiple, and sets the Lower and Upper bounds from
+// them.
+// Each value is the float64 closest to the exact multiple so that it
+// prints as the decimal value. Where the values are too close together to
+// be distinguished as float64 values the duplicates are merged. It will
+// panic if the values cannot be represented.
+func (t *Ticks) setVals(minVal, maxVal float64,
+	s niceStep, lo, hi *big.Int, n int64,
+) {
+	step := s.rat()
+	count := new(big.Int).Sub(hi, lo).Int64()/n + 1
+
+	t.Vals = make([]float64, 0, count)
+
+	var k, inc big.Int
+
+	inc.SetInt64(n)
+
+	for k.Set(lo); k.Cmp(hi) <= 0; k.Add(&k, &inc) {
+		v, _ := new(big.Rat).Mul(new(big.Rat).SetInt(&k), step).Float64()
+		if math.IsInf(v, 0) {
+			panicRangeTooLarge(minVal, maxVal)
+		}
+
+		if len(t.Vals) == 0 || v != t.Vals[len(t.Vals)-1] {
+			t.Vals = append(t.Vals, v)
+		}
+	}
+
+	t.Lower = t.Vals[0]
+	t.Upper = t.Vals[len(t.Vals)-1]
+}
+
+// NiceTicks returns a set of "nice" tick values for an axis covering the
+// range from minVal to maxVal. There will be no more than maxTicks values
+// and the step between them will be 1, 2 or 5 times a power of 10, much as
+// Roughly chooses a value close to a multiple of 5 or 10. It uses
+// Heckbert's "nice numbers for graph labels" algorithm. The one exception
+// is that with a maxTicks of 2 the only way to cover a range which
+// straddles a multiple of the step may be to use twice the step.
+//
+// Each tick is the float64 value closest to the exact multiple of the step
+// so that it can be printed without rounding errors. If the range is so
+// small that neighbouring ticks are the same float64 value they are shown
+// only once (so there may be fewer ticks and they may not be evenly
+// spaced).
+//
+// If minVal is greater than maxVal they are swapped. If they are equal the
+// range is widened around the value. It will panic if maxTicks is less than
+// 2, if either value is infinite or NaN or if the range is so large that
+// the ticks cannot be represented (the span of the range or the enclosing
+// ticks would exceed the largest float64).
+func NiceTicks(minVal, maxVal float64, maxTicks int) Ticks {
+	minVal, maxVal = checkTickParams(minVal, maxVal, maxTicks)
+
+	if minVal == maxVal {
+		delta := math.Abs(minVal) / 10 //nolint:mnd
+		if delta == 0 {
+			delta = 1
+		}
+
+		minVal -= delta
+		maxVal += delta
+	}
+
+	span := maxVal - minVal
+	if math.IsInf(span, 0) {
+		panicRangeTooLarge(minVal, maxVal)
+	}
+
+	r := niceNum(span, false).float()
+	if math.IsInf(r, 0) {
+		panicRangeTooLarge(minVal, maxVal)
+	}
+
+	var (
+		s      niceStep
+		lo, hi *big.Int
+	)
+
+	for ticks := maxTicks; ticks >= minTicks; ticks-- {
+		// the division may underflow for the very smallest ranges
+		s = niceNum(max(r/float64(ticks-1), math.SmallestNonzeroFloat64),
+			true)
+		lo, hi = tickBounds(minVal, maxVal, s)
+
+		if tickCountFits(lo, hi, maxTicks) {
+			break
+		}
+	}
+
+	t := Ticks{Precision: s.precision()}
+	every := int64(1)
+
+	// This can only happen with a maxTicks of 2 where the range needs 3
+	// ticks. Try the next nice step and if the range still straddles a
+	// multiple of it, keep the bounds and make the step span them. The
+	// precision needed is that of the bounds.
+	if !tickCountFits(lo, hi, maxTicks) {
+		wider := s.next()
+
+		wLo, wHi := tickBounds(minVal, maxVal, wider)
+		if tickCountFits(wLo, wHi, maxTicks) {
+			s, lo, hi = wider, wLo, wHi
+			t.Precision = s.precision()
+		} else {
+			every = new(big.Int).Sub(hi, lo).Int64()
+		}
+	}
+
+	t.setVals(minVal, maxVal, s, lo, hi, every)
+
+	t.Step = s.float()
+	if every != 1 {
+		t.Step = t.Upper - t.Lower
+	}
+
+	if math.IsInf(t.Step, 0) {
+		panicRangeTooLarge(minVal, maxVal)
+	}
+
+	t.setWidth()
+
+	return t
+}
+
+// NiceLogTicks returns a set of "nice" tick values for a logarithmic axis
+// covering the range from minVal to maxVal. The ticks will be at powers of
+// 10 and there will be no more than maxTicks of them; if the range covers
+// too many decades the ticks will be spaced several decades apart. The Step
+// is the ratio between successive ticks. Each value is the float64 closest
+// to the power of 10. Note that for the very smallest subnormal values the
+// Lower bound will be zero as there is no float64 power of 10 which is no
+// greater than them.
+//
+// If minVal is greater than maxVal they are swapped. It will panic if
+// maxTicks is less than 2, if either value is infinite or NaN, if either
+// value is not greater than zero or if the ticks (or the ratio between
+// them) would exceed the largest float64.
+func NiceLogTicks(minVal, maxVal float64, maxTicks int) Ticks {
+	minVal, maxVal = checkTickParams(minVal, maxVal, maxTicks)
+
+	if minVal <= 0 {
+		panic(fmt.Sprintf(
+			"Invalid logarithmic tick range [%g, %g],"+
+				" the values must be greater than zero",
+			minVal, maxVal))
+	}
+
+	// math.Log10 is not exact, particularly for subnormal values, so the
+	// exponents are adjusted to make sure that the ticks enclose the range
+	loExp := int(math.Floor(math.Log10(minVal)))
+	for pow10(loExp) > minVal {
+		loExp--
+	}
+
+	hiExp := int(math.Ceil(math.Log10(maxVal)))
+	for pow10(hiExp) < maxVal {
+		hiExp++
+	}
+
+	if hiExp == loExp {
+		hiExp++
+	}
+
+	decades := hiExp - loExp
+	stepExp := (decades + maxTicks - 2) / (maxTicks - 1)
+	count := (decades+stepExp-1)/stepExp + 1
+
+	t := Ticks{
+		Lower:     pow10(loExp),
+		Upper:     pow10(loExp + (count-1)*stepExp),
+		Step:      pow10(stepExp),
+		Vals:      make([]float64, 0, count),
+		Precision: max(0, -loExp),
+	}
+
+	if math.IsInf(t.Upper, 0) || math.IsInf(t.Step, 0) {
+		panicRangeTooLarge(minVal, maxVal)
+	}
+
+	for i := range count {
+		t.Vals = append(t.Vals, pow10(loExp+i*stepExp))
+	}
+
+	t.setWidth()
+
+	return t
+}
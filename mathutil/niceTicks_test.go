@@ -0,0 +1,358 @@
+package mathutil_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// diffTicks compares the actual Ticks against the expected values
+func diffTicks(t *testing.T, id string, act, exp mathutil.Ticks) {
+	t.Helper()
+
+	// the values should be exactly those closest to the decimal values
+	const epsilon = 0
+
+	testhelper.DiffFloat(t, id, "lower", act.Lower, exp.Lower, epsilon)
+	testhelper.DiffFloat(t, id, "upper", act.Upper, exp.Upper, epsilon)
+	testhelper.DiffFloat(t, id, "step", act.Step, exp.Step, epsilon)
+	testhelper.DiffFloatSlice(t, id, "vals", act.Vals, exp.Vals, epsilon)
+	testhelper.DiffInt(t, id, "precision", act.Precision, exp.Precision)
+	testhelper.DiffInt(t, id, "width", act.Width, exp.Width)
+}
+
+func TestNiceTicks(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		minVal, maxVal float64
+		maxTicks       int
+		expTicks       mathutil.Ticks
+	}{
+		{
+			ID: testhelper.MkID("bad max ticks"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid maximum number of ticks (1), it must be at least 2"),
+			minVal:   0,
+			maxVal:   1,
+			maxTicks: 1,
+		},
+		{
+			ID:       testhelper.MkID("0 - 100"),
+			minVal:   0,
+			maxVal:   100,
+			maxTicks: 6,
+			expTicks: mathutil.Ticks{
+				Lower:     0,
+				Upper:     100,
+				Step:      20,
+				Vals:      []float64{0, 20, 40, 60, 80, 100},
+				Precision: 0,
+				Width:     3,
+			},
+		},
+		{
+			ID:       testhelper.MkID("ragged range"),
+			minVal:   -3.7,
+			maxVal:   17.2,
+			maxTicks: 5,
+			expTicks: mathutil.Ticks{
+				Lower:     -10,
+				Upper:     20,
+				Step:      10,
+				Vals:      []float64{-10, 0, 10, 20},
+				Precision: 0,
+				Width:     3,
+			},
+		},
+		{
+			ID:       testhelper.MkID("small values, reversed"),
+			minVal:   0.034,
+			maxVal:   0.012,
+			maxTicks: 5,
+			expTicks: mathutil.Ticks{
+				Lower:     0.01,
+				Upper:     0.04,
+				Step:      0.01,
+				Vals:      []float64{0.01, 0.02, 0.03, 0.04},
+				Precision: 2,
+				Width:     4,
+			},
+		},
+		{
+			ID:       testhelper.MkID("single value"),
+			minVal:   0,
+			maxVal:   0,
+			maxTicks: 3,
+			expTicks: mathutil.Ticks{
+				Lower:     -1,
+				Upper:     1,
+				Step:      1,
+				Vals:      []float64{-1, 0, 1},
+				Precision: 0,
+				Width:     2,
+			},
+		},
+		{
+			ID:       testhelper.MkID("two ticks, next nice step"),
+			minVal:   0.5,
+			maxVal:   1.5,
+			maxTicks: 2,
+			expTicks: mathutil.Ticks{
+				Lower:     0,
+				Upper:     2,
+				Step:      2,
+				Vals:      []float64{0, 2},
+				Precision: 0,
+				Width:     1,
+			},
+		},
+		{
+			ID:       testhelper.MkID("two ticks, larger values"),
+			minVal:   68.4,
+			maxVal:   146.2,
+			maxTicks: 2,
+			expTicks: mathutil.Ticks{
+				Lower:     0,
+				Upper:     200,
+				Step:      200,
+				Vals:      []float64{0, 200},
+				Precision: 0,
+				Width:     3,
+			},
+		},
+		{
+			ID:       testhelper.MkID("two ticks, straddling zero"),
+			minVal:   -0.9,
+			maxVal:   1.1,
+			maxTicks: 2,
+			expTicks: mathutil.Ticks{
+				Lower:     -2,
+				Upper:     2,
+				Step:      4,
+				Vals:      []float64{-2, 2},
+				Precision: 0,
+				Width:     2,
+			},
+		},
+		{
+			ID:       testhelper.MkID("large values"),
+			minVal:   1e22,
+			maxVal:   5e22,
+			maxTicks: 9,
+			expTicks: mathutil.Ticks{
+				Lower: 1e22,
+				Upper: 5e22,
+				Step:  5e21,
+				Vals: []float64{
+					1e22, 1.5e22, 2e22, 2.5e22, 3e22,
+					3.5e22, 4e22, 4.5e22, 5e22,
+				},
+				Precision: 0,
+				Width:     23,
+			},
+		},
+		{
+			ID:       testhelper.MkID("very large values"),
+			minVal:   1e300,
+			maxVal:   1.7e300,
+			maxTicks: 5,
+			expTicks: mathutil.Ticks{
+				Lower: 1e300,
+				Upper: 1.8e300,
+				Step:  2e299,
+				Vals: []float64{
+					1e300, 1.2e300, 1.4e300, 1.6e300, 1.8e300,
+				},
+				Precision: 0,
+				Width:     301,
+			},
+		},
+		{
+			ID:       testhelper.MkID("tiny span, ticks merged"),
+			minVal:   1e16,
+			maxVal:   1e16 + 4,
+			maxTicks: 5,
+			expTicks: mathutil.Ticks{
+				Lower:     1e16,
+				Upper:     1e16 + 4,
+				Step:      1,
+				Vals:      []float64{1e16, 1e16 + 2, 1e16 + 4},
+				Precision: 0,
+				Width:     17,
+			},
+		},
+		{
+			ID: testhelper.MkID("span too large"),
+			ExpPanic: testhelper.MkExpPanic("Invalid tick range" +
+				" [-1e+308, 1e+308], the range is too large"),
+			minVal:   -1e308,
+			maxVal:   1e308,
+			maxTicks: 5,
+		},
+		{
+			ID: testhelper.MkID("ticks too large"),
+			ExpPanic: testhelper.MkExpPanic("Invalid tick range" +
+				" [0, 1.7e+308], the range is too large"),
+			minVal:   0,
+			maxVal:   1.7e308,
+			maxTicks: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			ticks := mathutil.NiceTicks(tc.minVal, tc.maxVal, tc.maxTicks)
+			diffTicks(t, tc.IDStr(), ticks, tc.expTicks)
+
+			if len(ticks.Vals) > tc.maxTicks {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: too many ticks: %d > %d",
+					len(ticks.Vals), tc.maxTicks)
+			}
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+// TestNiceTicksRandom checks that the ticks enclose the range and that
+// there are never more than the maximum number of them
+func TestNiceTicksRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6)) //nolint:gosec
+
+	for range 20000 {
+		scale := math.Pow10(r.IntN(20) - 10) //nolint:mnd
+		minVal := (r.Float64() - 0.5) * scale
+		maxVal := minVal + r.Float64()*scale
+		maxTicks := 2 + r.IntN(9) //nolint:mnd
+
+		ticks := mathutil.NiceTicks(minVal, maxVal, maxTicks)
+
+		id := fmt.Sprintf("[%g, %g], %d ticks", minVal, maxVal, maxTicks)
+		if len(ticks.Vals) > maxTicks || len(ticks.Vals) < 2 {
+			t.Errorf("%s: bad number of ticks: %v", id, ticks.Vals)
+		}
+
+		for i := 1; i < len(ticks.Vals); i++ {
+			if ticks.Vals[i] <= ticks.Vals[i-1] {
+				t.Errorf("%s: the ticks are not increasing: %v",
+					id, ticks.Vals)
+			}
+		}
+
+		if ticks.Lower > minVal || ticks.Upper < maxVal {
+			t.Errorf("%s: the ticks [%g, %g] do not enclose the range",
+				id, ticks.Lower, ticks.Upper)
+		}
+	}
+}
+
+func TestNiceLogTicks(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		minVal, maxVal float64
+		maxTicks       int
+		expTicks       mathutil.Ticks
+	}{
+		{
+			ID: testhelper.MkID("bad range"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid logarithmic tick range [0, 1]," +
+					" the values must be greater than zero"),
+			minVal:   0,
+			maxVal:   1,
+			maxTicks: 5,
+		},
+		{
+			ID:       testhelper.MkID("0.05 - 300"),
+			minVal:   0.05,
+			maxVal:   300,
+			maxTicks: 10,
+			expTicks: mathutil.Ticks{
+				Lower:     0.01,
+				Upper:     1000,
+				Step:      10,
+				Vals:      []float64{0.01, 0.1, 1, 10, 100, 1000},
+				Precision: 2,
+				Width:     7,
+			},
+		},
+		{
+			ID:       testhelper.MkID("many decades"),
+			minVal:   1,
+			maxVal:   1e9,
+			maxTicks: 4,
+			expTicks: mathutil.Ticks{
+				Lower:     1,
+				Upper:     1e9,
+				Step:      1e3,
+				Vals:      []float64{1, 1e3, 1e6, 1e9},
+				Precision: 0,
+				Width:     10,
+			},
+		},
+		{
+			ID:       testhelper.MkID("extreme decades"),
+			minVal:   1e-300,
+			maxVal:   1e300,
+			maxTicks: 5,
+			expTicks: mathutil.Ticks{
+				Lower:     1e-300,
+				Upper:     1e300,
+				Step:      1e150,
+				Vals:      []float64{1e-300, 1e-150, 1, 1e150, 1e300},
+				Precision: 300,
+				Width:     602,
+			},
+		},
+		{
+			ID:       testhelper.MkID("subnormal"),
+			minVal:   1e-310,
+			maxVal:   1e-300,
+			maxTicks: 3,
+			expTicks: mathutil.Ticks{
+				Lower:     1e-310,
+				Upper:     1e-300,
+				Step:      1e5,
+				Vals:      []float64{1e-310, 1e-305, 1e-300},
+				Precision: 310,
+				Width:     312,
+			},
+		},
+		{
+			ID:       testhelper.MkID("subnormal, inexact log"),
+			minVal:   1e-320,
+			maxVal:   1e-300,
+			maxTicks: 3,
+			expTicks: mathutil.Ticks{
+				Lower:     1e-320,
+				Upper:     1e-300,
+				Step:      1e10,
+				Vals:      []float64{1e-320, 1e-310, 1e-300},
+				Precision: 320,
+				Width:     322,
+			},
+		},
+		{
+			ID: testhelper.MkID("ticks too large"),
+			ExpPanic: testhelper.MkExpPanic("Invalid tick range" +
+				" [1e+300, 1.7e+308], the range is too large"),
+			minVal:   1e300,
+			maxVal:   1.7e308,
+			maxTicks: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			ticks := mathutil.NiceLogTicks(tc.minVal, tc.maxVal, tc.maxTicks)
+			diffTicks(t, tc.IDStr(), ticks, tc.expTicks)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
@@ -0,0 +1,8 @@
+package mathutil
+
+import "golang.org/x/exp/constraints"
+
+// Number is the set of Go's built-in integer and floating point types
+type Number interface {
+	constraints.Integer | constraints.Float
+}
@@ -1,12 +1,30 @@
 package mathutil
 
-import "golang.org/x/exp/constraints"
+import (
+	"math"
 
-const percentFactor = 100
+	"golang.org/x/exp/constraints"
+)
+
+const (
+	percentFactor    = 100
+	perMilleFactor   = 1000
+	basisPointFactor = 10000
+)
+
+// wideInteger is the set of integer types which are large enough to hold
+// the per mille and basis point factors; the 8-bit types are excluded.
+type wideInteger interface {
+	~int | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
 
 // FromPercent takes a percentage value and converts it into a value of the
 // same type. So, for instance, a pct value of 4% would be converted into a
 // value 0.04 by dividing it by 100.
+//
+// Note that for integer types the result is truncated, use
+// FromPercentRounded or FromPercentRem if this is not what you want.
 func FromPercent[T constraints.Float | constraints.Integer](pct T) T {
 	return pct / percentFactor
 }
@@ -17,3 +35,107 @@ func FromPercent[T constraints.Float | constraints.Integer](pct T) T {
 func ToPercent[T constraints.Float | constraints.Integer](v T) T {
 	return v * percentFactor
 }
+
+// FromPercentRounded takes an integer percentage value and converts it into
+// a value of the same type, rounding the result according to the mode.
+func FromPercentRounded[I constraints.Integer](pct I, mode RoundingMode) I {
+	return DivRound(pct, percentFactor, mode)
+}
+
+// FromPercentRem takes an integer percentage value and converts it into a
+// value of the same type. It returns the truncated value and the remainder
+// (in percent) so no information is lost.
+func FromPercentRem[I constraints.Integer](pct I) (v, rem I) {
+	return pct / percentFactor, pct % percentFactor
+}
+
+// FromPerMille takes a per mille (‰) value and converts it into a value of
+// the same type by dividing it by 1000. Note that for integer types the
+// result is truncated.
+func FromPerMille[T constraints.Float | wideInteger](pm T) T {
+	return pm / perMilleFactor
+}
+
+// ToPerMille takes a value and converts it to per mille (‰) by multiplying
+// it by 1000.
+func ToPerMille[T constraints.Float | wideInteger](v T) T {
+	return v * perMilleFactor
+}
+
+// FromPerMilleRounded takes an integer per mille value and converts it into
+// a value of the same type, rounding the result according to the mode.
+func FromPerMilleRounded[I wideInteger](pm I, mode RoundingMode) I {
+	return DivRound(pm, perMilleFactor, mode)
+}
+
+// FromBasisPoints takes a value in basis points (hundredths of a percent)
+// and converts it into a value of the same type by dividing it by
+// 10,000. Note that for integer types the result is truncated.
+func FromBasisPoints[T constraints.Float | wideInteger](bp T) T {
+	return bp / basisPointFactor
+}
+
+// ToBasisPoints takes a value and converts it to basis points (hundredths
+// of a percent) by multiplying it by 10,000.
+func ToBasisPoints[T constraints.Float | wideInteger](v T) T {
+	return v * basisPointFactor
+}
+
+// FromBasisPointsRounded takes an integer basis point value and converts it
+// into a value of the same type, rounding the result according to the mode.
+func FromBasisPointsRounded[I wideInteger](bp I, mode RoundingMode) I {
+	return DivRound(bp, basisPointFactor, mode)
+}
+
+// PercentOf returns pct percent of v. So, for instance, 4 percent of 250 is
+// 10. Note that for integer types the result is truncated and that the
+// intermediate product of pct and v may overflow.
+func PercentOf[T constraints.Float | constraints.Integer](pct, v T) T {
+	return v * pct / percentFactor
+}
+
+// PercentOfRounded returns pct percent of v, rounding the result according
+// to the mode. Note that the intermediate product of pct and v may
+// overflow.
+func PercentOfRounded[I constraints.Integer](pct, v I, mode RoundingMode) I {
+	return DivRound(v*pct, percentFactor, mode)
+}
+
+// PercentChange returns the change from the value from to the value to as
+// a percentage of the magnitude of from. So a change from 80 to 100 is a 25
+// percent change and a change from -80 to -100 is a -25 percent change. If
+// from is zero the result will be infinite (or NaN if to is also zero).
+func PercentChange[F constraints.Float](from, to F) F {
+	return ToPercent((to - from) / F(math.Abs(float64(from))))
+}
+
+// PercentagePointDiff returns the difference in percentage points between
+// two percentages. So a rate rising from 4% to 5% is a rise of 1 percentage
+// point (though it is a 25 percent change).
+func PercentagePointDiff[T constraints.Float | constraints.Integer](
+	fromPct, toPct T,
+) T {
+	return toPct - fromPct
+}
+
+// ReversePercentIncrease returns the value which, when increased by pct
+// percent, gives v. For instance it will give the pre-tax price from a
+// post-tax price and the tax rate; a price of 120 including tax at 20% is
+// 100 before tax.
+func ReversePercentIncrease[F constraints.Float](v, pct F) F {
+	return v / (1 + FromPercent(pct))
+}
+
+// CompoundGrowth returns the value of v after growing by ratePct percent in
+// each of the given number of periods.
+func CompoundGrowth[F constraints.Float](v, ratePct F, periods float64) F {
+	return v * F(math.Pow(float64(1+FromPercent(ratePct)), periods))
+}
+
+// CAGR returns the compound annual growth rate, as a percentage, which
+// takes the value start to the value end over the given number of periods
+// (usually years). It is the inverse of CompoundGrowth. The result is NaN if
+// start and end have differing signs.
+func CAGR[F constraints.Float](start, end F, periods float64) F {
+	return ToPercent(F(math.Pow(float64(end/start), 1/periods)) - 1)
+}
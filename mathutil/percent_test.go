@@ -0,0 +1,162 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestPercentConversions(t *testing.T) {
+	const epsilon = 1e-12
+
+	testhelper.DiffFloat(t, "FromPercent", "", mathutil.FromPercent(4.0),
+		0.04, epsilon)
+	testhelper.DiffFloat(t, "ToPercent", "", mathutil.ToPercent(0.04),
+		4.0, epsilon)
+	testhelper.DiffFloat(t, "FromPerMille", "", mathutil.FromPerMille(4.0),
+		0.004, epsilon)
+	testhelper.DiffFloat(t, "ToPerMille", "", mathutil.ToPerMille(0.004),
+		4.0, epsilon)
+	testhelper.DiffFloat(t, "FromBasisPoints", "",
+		mathutil.FromBasisPoints(25.0), 0.0025, epsilon)
+	testhelper.DiffFloat(t, "ToBasisPoints", "",
+		mathutil.ToBasisPoints(0.0025), 25.0, epsilon)
+	testhelper.DiffInt(t, "ToBasisPoints (int)", "",
+		mathutil.ToBasisPoints(int16(3)), 30000)
+}
+
+func TestPercentIntRounding(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		pct    int
+		mode   mathutil.RoundingMode
+		expVal int
+	}{
+		{
+			ID:     testhelper.MkID("4%, half even"),
+			pct:    4,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("50%, half even"),
+			pct:    50,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("150%, half even"),
+			pct:    150,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 2,
+		},
+		{
+			ID:     testhelper.MkID("50%, half up"),
+			pct:    50,
+			mode:   mathutil.RoundHalfUp,
+			expVal: 1,
+		},
+		{
+			ID:     testhelper.MkID("-50%, half up"),
+			pct:    -50,
+			mode:   mathutil.RoundHalfUp,
+			expVal: -1,
+		},
+		{
+			ID:     testhelper.MkID("4%, ceiling"),
+			pct:    4,
+			mode:   mathutil.RoundCeiling,
+			expVal: 1,
+		},
+		{
+			ID:     testhelper.MkID("-4%, floor"),
+			pct:    -4,
+			mode:   mathutil.RoundFloor,
+			expVal: -1,
+		},
+		{
+			ID:     testhelper.MkID("-4%, ceiling"),
+			pct:    -4,
+			mode:   mathutil.RoundCeiling,
+			expVal: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		v := mathutil.FromPercentRounded(tc.pct, tc.mode)
+		testhelper.DiffInt(t, tc.IDStr(), "value", v, tc.expVal)
+	}
+
+	v, rem := mathutil.FromPercentRem(uint8(254))
+	testhelper.DiffInt(t, "FromPercentRem", "value", v, 2)
+	testhelper.DiffInt(t, "FromPercentRem", "remainder", rem, 54)
+
+	testhelper.DiffInt(t, "FromPerMilleRounded", "value",
+		mathutil.FromPerMilleRounded(1500, mathutil.RoundHalfDown), 1)
+	testhelper.DiffInt(t, "FromBasisPointsRounded", "value",
+		mathutil.FromBasisPointsRounded(15000, mathutil.RoundHalfUp), 2)
+	testhelper.DiffInt(t, "PercentOfRounded", "value",
+		mathutil.PercentOfRounded(15, 30, mathutil.RoundHalfEven), 4)
+}
+
+func TestPercentArithmetic(t *testing.T) {
+	const epsilon = 1e-9
+
+	testCases := []struct {
+		testhelper.ID
+		val    float64
+		expVal float64
+	}{
+		{
+			ID:     testhelper.MkID("PercentOf"),
+			val:    mathutil.PercentOf(4.0, 250.0),
+			expVal: 10,
+		},
+		{
+			ID:     testhelper.MkID("PercentChange - rise"),
+			val:    mathutil.PercentChange(80.0, 100.0),
+			expVal: 25,
+		},
+		{
+			ID:     testhelper.MkID("PercentChange - fall"),
+			val:    mathutil.PercentChange(100.0, 80.0),
+			expVal: -20,
+		},
+		{
+			ID:     testhelper.MkID("PercentChange - negative values"),
+			val:    mathutil.PercentChange(-80.0, -100.0),
+			expVal: -25,
+		},
+		{
+			ID:     testhelper.MkID("PercentChange - from zero"),
+			val:    mathutil.PercentChange(0.0, 1.0),
+			expVal: math.Inf(1),
+		},
+		{
+			ID:     testhelper.MkID("PercentagePointDiff"),
+			val:    mathutil.PercentagePointDiff(4.0, 5.0),
+			expVal: 1,
+		},
+		{
+			ID:     testhelper.MkID("ReversePercentIncrease"),
+			val:    mathutil.ReversePercentIncrease(120.0, 20.0),
+			expVal: 100,
+		},
+		{
+			ID:     testhelper.MkID("CompoundGrowth"),
+			val:    mathutil.CompoundGrowth(100.0, 10.0, 2),
+			expVal: 121,
+		},
+		{
+			ID:     testhelper.MkID("CAGR"),
+			val:    mathutil.CAGR(100.0, 121.0, 2),
+			expVal: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffFloat(t, tc.IDStr(), "value", tc.val, tc.expVal, epsilon)
+	}
+}
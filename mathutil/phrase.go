@@ -0,0 +1,235 @@
+package mathutil
+
+import (
+	"math"
+	"strconv"
+)
+
+// maxPhraseSigFigs is used to remove any floating point artefacts, such as
+// those from division by a scale, before a number is shown
+const maxPhraseSigFigs = 15
+
+// maxScaledPart is the multiple of the largest scale name at which a value
+// is shown in exponent form instead; so 1e25 is shown as "1e+25" rather than
+// "10000000 quintillion"
+const maxScaledPart = 1000
+
+// Qualifier gives the word used to introduce an approximate value. It is
+// used if the approximation differs from the true value by no more than
+// MaxPct percent.
+type Qualifier struct {
+	MaxPct float64
+	Word   string
+}
+
+// ScaleName gives the name for a power of ten (Exp) such as "million" for
+// an Exp of 6.
+type ScaleName struct {
+	Exp  int
+	Name string
+}
+
+// FractionName gives the singular and plural names for a fraction, such as
+// "third" and "thirds".
+type FractionName struct {
+	Singular string
+	Plural   string
+}
+
+// NumberWords holds the words used by ApproxPhrase to describe a number. A
+// different NumberWords can be constructed to support other languages.
+//
+// The Qualifiers should be in increasing order of MaxPct, the first one
+// whose MaxPct is no less than the difference between the phrase and the
+// value is used; if none match the last one is used.
+//
+// Units gives the words for small whole numbers, Units[i] being the word
+// for i.
+//
+// The Scales should be in increasing order of Exp.
+//
+// Fractions maps a denominator to its name. The Article is used in place of
+// the numerator for a fraction with a numerator of one, so "a half" rather
+// than "one half".
+type NumberWords struct {
+	Minus      string
+	Article    string
+	Qualifiers []Qualifier
+	Units      []string
+	Scales     []ScaleName
+	Fractions  map[int64]FractionName
+}
+
+// englishWords returns the NumberWords common to the short and long scale
+// English number words.
+func englishWords() NumberWords {
+	return NumberWords{
+		Minus:   "minus",
+		Article: "a",
+		Qualifiers: []Qualifier{
+			{MaxPct: 0.5, Word: "roughly"},
+			{MaxPct: 100, Word: "about"},
+		},
+		Units: []string{
+			"zero", "one", "two", "three", "four",
+			"five", "six", "seven", "eight", "nine",
+			"ten", "eleven", "twelve", "thirteen", "fourteen",
+			"fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+			"twenty",
+		},
+		Fractions: map[int64]FractionName{
+			2:  {Singular: "half", Plural: "halves"},
+			3:  {Singular: "third", Plural: "thirds"},
+			4:  {Singular: "quarter", Plural: "quarters"},
+			5:  {Singular: "fifth", Plural: "fifths"},
+			8:  {Singular: "eighth", Plural: "eighths"},
+			10: {Singular: "tenth", Plural: "tenths"},
+		},
+	}
+}
+
+// EnglishShortScale returns the English NumberWords using the short scale
+// where a billion is a thousand million.
+func EnglishShortScale() NumberWords {
+	nw := englishWords()
+	nw.Scales = []ScaleName{
+		{Exp: 3, Name: "thousand"},
+		{Exp: 6, Name: "million"},
+		{Exp: 9, Name: "billion"},
+		{Exp: 12, Name: "trillion"},
+		{Exp: 15, Name: "quadrillion"},
+		{Exp: 18, Name: "quintillion"},
+	}
+
+	return nw
+}
+
+// EnglishLongScale returns the English NumberWords using the long scale
+// where a billion is a million million.
+func EnglishLongScale() NumberWords {
+	nw := englishWords()
+	nw.Scales = []ScaleName{
+		{Exp: 3, Name: "thousand"},
+		{Exp: 6, Name: "million"},
+		{Exp: 9, Name: "milliard"},
+		{Exp: 12, Name: "billion"},
+		{Exp: 15, Name: "billiard"},
+		{Exp: 18, Name: "trillion"},
+	}
+
+	return nw
+}
+
+// qualify returns the phrase preceded by the appropriate qualifier for the
+// difference between the phrase value, pv, and the true value, v.
+func (nw NumberWords) qualify(phrase string, pv, v float64) string {
+	if pv == v || len(nw.Qualifiers) == 0 {
+		return phrase
+	}
+
+	diff := ToPercent(math.Abs((pv - v) / v))
+
+	q := nw.Qualifiers[len(nw.Qualifiers)-1]
+	for _, candidate := range nw.Qualifiers {
+		if diff <= candidate.MaxPct {
+			q = candidate
+			break
+		}
+	}
+
+	return q.Word + " " + phrase
+}
+
+// number returns the words for v. Whole numbers small enough to have an
+// entry in Units are given as words, otherwise the digits are used.
+func (nw NumberWords) number(v float64) string {
+	v = RoundSigFigs(v, maxPhraseSigFigs, RoundHalfEven)
+
+	if v == math.Trunc(v) && v < float64(len(nw.Units)) {
+		return nw.Units[int(v)]
+	}
+
+	return strconv.FormatFloat(v, 'f', -1, float64Bits)
+}
+
+// fraction returns the words for the fraction r and true if it can be
+// expressed in words, false otherwise.
+func (nw NumberWords) fraction(r Rational) (string, bool) {
+	fn, ok := nw.Fractions[r.D]
+	if !ok || r.N <= 0 || r.N >= r.D {
+		return "", false
+	}
+
+	if r.N == 1 {
+		return nw.Article + " " + fn.Singular, true
+	}
+
+	if r.N >= int64(len(nw.Units)) {
+		return "", false
+	}
+
+	return nw.Units[r.N] + " " + fn.Plural, true
+}
+
+// scaled returns the words for the non-negative value v using the largest
+// scale name no greater than v. A value too large for the scale names is
+// given in exponent form.
+func (nw NumberWords) scaled(v float64) string {
+	if n := len(nw.Scales); n > 0 &&
+		v >= maxScaledPart*pow10(nw.Scales[n-1].Exp) {
+		return strconv.FormatFloat(
+			RoundSigFigs(v, maxPhraseSigFigs, RoundHalfEven),
+			'g', -1, float64Bits)
+	}
+
+	for i := len(nw.Scales) - 1; i >= 0; i-- {
+		s := nw.Scales[i]
+
+		scale := pow10(s.Exp)
+		if v >= scale {
+			return nw.number(v/scale) + " " + s.Name
+		}
+	}
+
+	return nw.number(v)
+}
+
+// ApproxPhrase returns a phrase describing v in the way a person might say
+// it. So, for instance, 1,234,567 might become "about 1.2 million", 0.498
+// might become "roughly a half" and 0.33 might become "about a third". The
+// accuracy is a percentage as for Roughly and RationalApproximation; values
+// with a magnitude less than one are described as a simple fraction if
+// RationalApproximation finds one that has a name, otherwise the value is
+// first passed to Roughly.
+//
+// If the phrase describes the value exactly it is not qualified. Infinite
+// and NaN values are described using their string form.
+func ApproxPhrase(v, accuracy float64, nw NumberWords) string {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return strconv.FormatFloat(v, 'g', -1, float64Bits)
+	}
+
+	if v == 0 {
+		return nw.number(0)
+	}
+
+	absV := math.Abs(v)
+
+	prefix := ""
+	if v < 0 {
+		prefix = nw.Minus + " "
+	}
+
+	if absV < 1 {
+		r, err := RationalApproximation(absV, accuracy)
+		if err == nil {
+			if phrase, ok := nw.fraction(r); ok {
+				return nw.qualify(prefix+phrase, r.AsFloat64(), absV)
+			}
+		}
+	}
+
+	rough := Roughly(absV, accuracy)
+
+	return nw.qualify(prefix+nw.scaled(rough), rough, absV)
+}
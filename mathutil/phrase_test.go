@@ -0,0 +1,168 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestApproxPhrase(t *testing.T) {
+	short := mathutil.EnglishShortScale()
+	long := mathutil.EnglishLongScale()
+
+	testCases := []struct {
+		testhelper.ID
+		v         float64
+		accuracy  float64
+		nw        mathutil.NumberWords
+		expPhrase string
+	}{
+		{
+			ID:        testhelper.MkID("zero"),
+			v:         0,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "zero",
+		},
+		{
+			ID:        testhelper.MkID("1,234,567"),
+			v:         1234567,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "about 1.2 million",
+		},
+		{
+			ID:        testhelper.MkID("-1,234,567"),
+			v:         -1234567,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "about minus 1.2 million",
+		},
+		{
+			ID:        testhelper.MkID("0.498"),
+			v:         0.498,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "roughly a half",
+		},
+		{
+			ID:        testhelper.MkID("0.33"),
+			v:         0.33,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "about a third",
+		},
+		{
+			ID:        testhelper.MkID("exactly three quarters"),
+			v:         0.75,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "three quarters",
+		},
+		{
+			ID:        testhelper.MkID("no fraction name"),
+			v:         0.0123,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "about 0.012",
+		},
+		{
+			ID:        testhelper.MkID("below 0.01"),
+			v:         0.000777,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "about 0.0008",
+		},
+		{
+			ID:        testhelper.MkID("below 0.0001"),
+			v:         3.3e-5,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "roughly 0.000033",
+		},
+		{
+			ID:        testhelper.MkID("tiny value"),
+			v:         1e-7,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "roughly 0.0000001",
+		},
+		{
+			ID:        testhelper.MkID("small whole number"),
+			v:         11.9,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "about twelve",
+		},
+		{
+			ID:        testhelper.MkID("whole number of millions"),
+			v:         4987654,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "roughly five million",
+		},
+		{
+			ID:        testhelper.MkID("short scale billion"),
+			v:         2.1e9,
+			accuracy:  1,
+			nw:        short,
+			expPhrase: "2.1 billion",
+		},
+		{
+			ID:        testhelper.MkID("long scale milliard"),
+			v:         2.1e9,
+			accuracy:  1,
+			nw:        long,
+			expPhrase: "2.1 milliard",
+		},
+		{
+			ID:        testhelper.MkID("long scale billion"),
+			v:         3e12,
+			accuracy:  5,
+			nw:        long,
+			expPhrase: "three billion",
+		},
+		{
+			ID:        testhelper.MkID("largest scale name"),
+			v:         9.87e20,
+			accuracy:  1,
+			nw:        short,
+			expPhrase: "roughly 990 quintillion",
+		},
+		{
+			ID:        testhelper.MkID("beyond the scale names"),
+			v:         1e25,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "1e+25",
+		},
+		{
+			ID:        testhelper.MkID("beyond the scale names, approximate"),
+			v:         1.234e25,
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "about 1.2e+25",
+		},
+		{
+			ID:        testhelper.MkID("beyond the long scale names, negative"),
+			v:         -4.56e30,
+			accuracy:  1,
+			nw:        long,
+			expPhrase: "about minus 4.6e+30",
+		},
+		{
+			ID:        testhelper.MkID("infinity"),
+			v:         math.Inf(1),
+			accuracy:  5,
+			nw:        short,
+			expPhrase: "+Inf",
+		},
+	}
+
+	for _, tc := range testCases {
+		phrase := mathutil.ApproxPhrase(tc.v, tc.accuracy, tc.nw)
+		testhelper.DiffString(t, tc.IDStr(), "phrase", phrase, tc.expPhrase)
+	}
+}
@@ -0,0 +1,78 @@
+package mathutil
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PreferredSeries identifies a series of preferred numbers. Each series
+// gives a set of values in the interval [1, 10) which are repeated in every
+// decade.
+type PreferredSeries int
+
+// These are the available series of preferred numbers
+const (
+	// SeriesOneTwoFive is the series 1, 2, 5
+	SeriesOneTwoFive PreferredSeries = iota
+	// SeriesR5 is the Renard series with 5 values per decade
+	SeriesR5
+	// SeriesR10 is the Renard series with 10 values per decade
+	SeriesR10
+	// SeriesR20 is the Renard series with 20 values per decade
+	SeriesR20
+	// SeriesE6 is the E-series with 6 values per decade
+	SeriesE6
+	// SeriesE12 is the E-series with 12 values per decade
+	SeriesE12
+	// SeriesE24 is the E-series with 24 values per decade
+	SeriesE24
+	// SeriesE96 is the E-series with 96 values per decade
+	SeriesE96
+)
+
+var preferredSeriesVals = map[PreferredSeries][]float64{
+	SeriesOneTwoFive: {1, 2, 5},
+	SeriesR5:         {1.00, 1.60, 2.50, 4.00, 6.30},
+	SeriesR10: {
+		1.00, 1.25, 1.60, 2.00, 2.50, 3.15, 4.00, 5.00, 6.30, 8.00,
+	},
+	SeriesR20: {
+		1.00, 1.12, 1.25, 1.40, 1.60, 1.80, 2.00, 2.24, 2.50, 2.80,
+		3.15, 3.55, 4.00, 4.50, 5.00, 5.60, 6.30, 7.10, 8.00, 9.00,
+	},
+	SeriesE6: {1.0, 1.5, 2.2, 3.3, 4.7, 6.8},
+	SeriesE12: {
+		1.0, 1.2, 1.5, 1.8, 2.2, 2.7, 3.3, 3.9, 4.7, 5.6, 6.8, 8.2,
+	},
+	SeriesE24: {
+		1.0, 1.1, 1.2, 1.3, 1.5, 1.6, 1.8, 2.0, 2.2, 2.4, 2.7, 3.0,
+		3.3, 3.6, 3.9, 4.3, 4.7, 5.1, 5.6, 6.2, 6.8, 7.5, 8.2, 9.1,
+	},
+	SeriesE96: {
+		1.00, 1.02, 1.05, 1.07, 1.10, 1.13, 1.15, 1.18, 1.21, 1.24, 1.27, 1.30,
+		1.33, 1.37, 1.40, 1.43, 1.47, 1.50, 1.54, 1.58, 1.62, 1.65, 1.69, 1.74,
+		1.78, 1.82, 1.87, 1.91, 1.96, 2.00, 2.05, 2.10, 2.15, 2.21, 2.26, 2.32,
+		2.37, 2.43, 2.49, 2.55, 2.61, 2.67, 2.74, 2.80, 2.87, 2.94, 3.01, 3.09,
+		3.16, 3.24, 3.32, 3.40, 3.48, 3.57, 3.65, 3.74, 3.83, 3.92, 4.02, 4.12,
+		4.22, 4.32, 4.42, 4.53, 4.64, 4.75, 4.87, 4.99, 5.11, 5.23, 5.36, 5.49,
+		5.62, 5.76, 5.90, 6.04, 6.19, 6.34, 6.49, 6.65, 6.81, 6.98, 7.15, 7.32,
+		7.50, 7.68, 7.87, 8.06, 8.25, 8.45, 8.66, 8.87, 9.09, 9.31, 9.53, 9.76,
+	},
+}
+
+// values returns the values in the series. It will panic if the series is
+// not known.
+func (s PreferredSeries) values() []float64 {
+	vals, ok := preferredSeriesVals[s]
+	if !ok {
+		panic(fmt.Sprintf("Unknown preferred number series: %d", int(s)))
+	}
+
+	return vals
+}
+
+// Values returns a copy of the values in the series for the decade [1, 10).
+// It will panic if the series is not known.
+func (s PreferredSeries) Values() []float64 {
+	return slices.Clone(s.values())
+}
@@ -0,0 +1,84 @@
+package mathutil_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestPreferredSeries(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		s        mathutil.PreferredSeries
+		expCount int
+	}{
+		{
+			ID:       testhelper.MkID("1-2-5"),
+			s:        mathutil.SeriesOneTwoFive,
+			expCount: 3,
+		},
+		{
+			ID:       testhelper.MkID("R5"),
+			s:        mathutil.SeriesR5,
+			expCount: 5,
+		},
+		{
+			ID:       testhelper.MkID("R10"),
+			s:        mathutil.SeriesR10,
+			expCount: 10,
+		},
+		{
+			ID:       testhelper.MkID("R20"),
+			s:        mathutil.SeriesR20,
+			expCount: 20,
+		},
+		{
+			ID:       testhelper.MkID("E6"),
+			s:        mathutil.SeriesE6,
+			expCount: 6,
+		},
+		{
+			ID:       testhelper.MkID("E12"),
+			s:        mathutil.SeriesE12,
+			expCount: 12,
+		},
+		{
+			ID:       testhelper.MkID("E24"),
+			s:        mathutil.SeriesE24,
+			expCount: 24,
+		},
+		{
+			ID:       testhelper.MkID("E96"),
+			s:        mathutil.SeriesE96,
+			expCount: 96,
+		},
+		{
+			ID: testhelper.MkID("unknown"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Unknown preferred number series: 99"),
+			s: mathutil.PreferredSeries(99),
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			vals := tc.s.Values()
+			testhelper.DiffInt(t, tc.IDStr(), "count", len(vals), tc.expCount)
+
+			for i, v := range vals {
+				if v < 1 || v >= 10 {
+					t.Log(tc.IDStr())
+					t.Errorf("\t: value[%d] (%g) is not in [1, 10)", i, v)
+				}
+
+				if i > 0 && v <= vals[i-1] {
+					t.Log(tc.IDStr())
+					t.Errorf("\t: value[%d] (%g) is not ascending", i, v)
+				}
+			}
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
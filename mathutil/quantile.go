@@ -0,0 +1,322 @@
+package mathutil
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+var (
+	errQuantileNoVals = errors.New("there are no values to take a quantile of")
+	errQuantileNaN    = errors.New("the values include a NaN")
+)
+
+// QuantileMethod selects one of the nine methods of calculating a sample
+// quantile given by Hyndman and Fan (1996). The names follow those used by
+// numpy and the comment on each gives the number used by Hyndman and Fan
+// (and by R).
+type QuantileMethod int
+
+// These are the available QuantileMethod values. Methods 1 to 3 give one of
+// the sample values, the others interpolate between them.
+const (
+	// QuantileLinear is method 7, the default in R and numpy
+	QuantileLinear QuantileMethod = iota
+	// QuantileInvertedCDF is method 1, the inverse of the empirical
+	// distribution function
+	QuantileInvertedCDF
+	// QuantileAveragedInvertedCDF is method 2, as method 1 but averaging at
+	// discontinuities
+	QuantileAveragedInvertedCDF
+	// QuantileClosestObservation is method 3, the nearest even order
+	// statistic
+	QuantileClosestObservation
+	// QuantileInterpolatedInvertedCDF is method 4, linear interpolation of
+	// the empirical distribution function
+	QuantileInterpolatedInvertedCDF
+	// QuantileHazen is method 5, a piecewise linear function where the
+	// knots are the midpoints of the steps of the empirical distribution
+	// function
+	QuantileHazen
+	// QuantileWeibull is method 6, the method used by Minitab and SPSS
+	QuantileWeibull
+	// QuantileMedianUnbiased is method 8, approximately median-unbiased
+	// regardless of the distribution; recommended by Hyndman and Fan
+	QuantileMedianUnbiased
+	// QuantileNormalUnbiased is method 9, approximately unbiased if the
+	// values are normally distributed
+	QuantileNormalUnbiased
+)
+
+// String returns a string value for the QuantileMethod
+func (m QuantileMethod) String() string {
+	switch m {
+	case QuantileLinear:
+		return "QuantileLinear"
+	case QuantileInvertedCDF:
+		return "QuantileInvertedCDF"
+	case QuantileAveragedInvertedCDF:
+		return "QuantileAveragedInvertedCDF"
+	case QuantileClosestObservation:
+		return "QuantileClosestObservation"
+	case QuantileInterpolatedInvertedCDF:
+		return "QuantileInterpolatedInvertedCDF"
+	case QuantileHazen:
+		return "QuantileHazen"
+	case QuantileWeibull:
+		return "QuantileWeibull"
+	case QuantileMedianUnbiased:
+		return "QuantileMedianUnbiased"
+	case QuantileNormalUnbiased:
+		return "QuantileNormalUnbiased"
+	}
+
+	return fmt.Sprintf("QuantileMethod(%d)", int(m))
+}
+
+// quantile returns the q'th quantile of n values using the method. The
+// order statistics are obtained through x which takes a zero-based index.
+// It will panic if the method is not known.
+func (m QuantileMethod) quantile(n int, q float64,
+	x func(i int) float64,
+) float64 {
+	fn := float64(n)
+
+	// order returns the zero-based index corresponding to the one-based
+	// index h, clamped to the range of the values
+	order := func(h float64) int {
+		return min(max(int(h), 1), n) - 1
+	}
+
+	// interp interpolates between the order statistics either side of the
+	// one-based position h
+	interp := func(h float64) float64 {
+		switch {
+		case h <= 1:
+			return x(0)
+		case h >= fn:
+			return x(n - 1)
+		}
+
+		lo := math.Floor(h)
+		xLo := x(int(lo) - 1)
+
+		if h == lo { // avoid multiplying an infinite difference by zero
+			return xLo
+		}
+
+		return xLo + (h-lo)*(x(int(lo))-xLo)
+	}
+
+	switch m {
+	case QuantileInvertedCDF:
+		return x(order(math.Ceil(fn * q)))
+	case QuantileAveragedInvertedCDF:
+		// ceil(h - 1/2) and floor(h + 1/2) where h is n*q + 1/2
+		lo, hi := order(math.Ceil(fn*q)), order(math.Floor(fn*q+1))
+		return (x(lo) + x(hi)) / 2 //nolint:mnd
+	case QuantileClosestObservation:
+		return x(order(math.RoundToEven(fn * q)))
+	case QuantileInterpolatedInvertedCDF:
+		return interp(fn * q)
+	case QuantileHazen:
+		return interp(fn*q + 0.5) //nolint:mnd
+	case QuantileWeibull:
+		return interp((fn + 1) * q)
+	case QuantileLinear:
+		return interp((fn-1)*q + 1)
+	case QuantileMedianUnbiased:
+		return interp((fn+1.0/3.0)*q + 1.0/3.0) //nolint:mnd
+	case QuantileNormalUnbiased:
+		return interp((fn+0.25)*q + 0.375) //nolint:mnd
+	}
+
+	panic(fmt.Sprintf("Invalid quantile method: %s", m))
+}
+
+// checkQuantileArgs returns a non-nil error if there are no values, if any
+// of them are NaN or if any of the quantiles are outside the range [0, 1]
+func checkQuantileArgs[F constraints.Float](vals []F, qs ...float64) error {
+	if len(vals) == 0 {
+		return errQuantileNoVals
+	}
+
+	for _, q := range qs {
+		if !(q >= 0 && q <= 1) {
+			return fmt.Errorf("bad quantile (%g), it must be in the range [0, 1]",
+				q)
+		}
+	}
+
+	if slices.ContainsFunc(vals, func(v F) bool { return v != v }) {
+		return errQuantileNaN
+	}
+
+	return nil
+}
+
+// partition partitions a around a pivot chosen from the median of three
+// values and returns the bounds of the values equal to the pivot. Values
+// before lt are less than the pivot, values from lt up to (but not
+// including) gt are equal to it and values from gt onwards are greater.
+// Grouping the values equal to the pivot means that values with many
+// duplicates are partitioned as quickly as distinct values.
+func partition[F constraints.Float](a []F) (lt, gt int) {
+	mid, last := len(a)/2, len(a)-1 //nolint:mnd
+
+	// order the first, middle and last values and use the middle as pivot
+	if a[mid] < a[0] {
+		a[mid], a[0] = a[0], a[mid]
+	}
+
+	if a[last] < a[0] {
+		a[last], a[0] = a[0], a[last]
+	}
+
+	if a[last] < a[mid] {
+		a[last], a[mid] = a[mid], a[last]
+	}
+
+	pivot := a[mid]
+
+	lt, gt = 0, len(a)
+
+	for i := 0; i < gt; {
+		switch {
+		case a[i] < pivot:
+			a[i], a[lt] = a[lt], a[i]
+			lt++
+			i++
+		case a[i] > pivot:
+			gt--
+			a[i], a[gt] = a[gt], a[i]
+		default:
+			i++
+		}
+	}
+
+	return lt, gt
+}
+
+// quickselect reorders a so that the value at index k is the value that
+// would be there if a were sorted, with no greater values before it and no
+// lesser values after it. It returns that value.
+func quickselect[F constraints.Float](a []F, k int) F {
+	lo, hi := 0, len(a)
+
+	for hi-lo > 1 {
+		lt, gt := partition(a[lo:hi])
+		lt, gt = lo+lt, lo+gt
+
+		switch {
+		case k < lt:
+			hi = lt
+		case k >= gt:
+			lo = gt
+		default:
+			return a[k]
+		}
+	}
+
+	return a[k]
+}
+
+// Quantile returns the q'th quantile of the values calculated using the
+// given method. The quantile, q, must be in the range [0, 1]; a value of
+// 0.5 gives the median. A copy of the values is reordered using the
+// quickselect algorithm so no full sort is needed and the values passed are
+// not changed.
+//
+// A non-nil error is returned if there are no values, if any of them are
+// NaN or if q is out of range.
+func Quantile[F constraints.Float](vals []F, q float64, m QuantileMethod) (
+	F, error,
+) {
+	if err := checkQuantileArgs(vals, q); err != nil {
+		return 0, err
+	}
+
+	a := slices.Clone(vals)
+
+	// Selecting the k'th value leaves the greater values to the right of it
+	// so the next order statistic is the least of those. The order
+	// statistics are always requested in increasing order.
+	selected := -1
+	x := func(i int) float64 {
+		switch {
+		case selected < 0:
+			quickselect(a, i)
+		case i == selected+1:
+			j := selected + 1 + ArgMin(a[selected+1:], TieFirst)
+			a[i], a[j] = a[j], a[i]
+		case i != selected:
+			quickselect(a, i)
+		}
+
+		selected = i
+
+		return float64(a[i])
+	}
+
+	return F(m.quantile(len(a), q, x)), nil
+}
+
+// Quantiles returns the quantiles of the values for each of the qs using
+// the given method. The values are copied and the copy is sorted so the
+// values passed are not changed.
+//
+// A non-nil error is returned if there are no values, if any of them are
+// NaN or if any of the qs are out of range.
+func Quantiles[F constraints.Float](vals []F, m QuantileMethod,
+	qs ...float64,
+) ([]F, error) {
+	if err := checkQuantileArgs(vals, qs...); err != nil {
+		return nil, err
+	}
+
+	a := slices.Clone(vals)
+	slices.Sort(a)
+
+	x := func(i int) float64 { return float64(a[i]) }
+
+	results := make([]F, 0, len(qs))
+	for _, q := range qs {
+		results = append(results, F(m.quantile(len(a), q, x)))
+	}
+
+	return results, nil
+}
+
+// Median returns the median of the values. A non-nil error is returned if
+// there are no values or if any of them are NaN.
+func Median[F constraints.Float](vals []F) (F, error) {
+	const half = 0.5
+
+	return Quantile(vals, half, QuantileLinear)
+}
+
+// Percentile returns the pct'th percentile of the values calculated using
+// the given method. The percentage, pct, must be in the range [0, 100] and
+// is converted to a quantile using FromPercent.
+func Percentile[F constraints.Float](vals []F, pct float64, m QuantileMethod) (
+	F, error,
+) {
+	return Quantile(vals, FromPercent(pct), m)
+}
+
+// Percentiles returns the percentiles of the values for each of the
+// percentages in pcts using the given method. Each percentage must be in
+// the range [0, 100].
+func Percentiles[F constraints.Float](vals []F, m QuantileMethod,
+	pcts ...float64,
+) ([]F, error) {
+	qs := make([]float64, 0, len(pcts))
+	for _, pct := range pcts {
+		qs = append(qs, FromPercent(pct))
+	}
+
+	return Quantiles(vals, m, qs...)
+}
@@ -0,0 +1,297 @@
+package mathutil_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// quantileMethods lists the quantile methods in Hyndman and Fan order
+var quantileMethods = []mathutil.QuantileMethod{
+	mathutil.QuantileInvertedCDF,
+	mathutil.QuantileAveragedInvertedCDF,
+	mathutil.QuantileClosestObservation,
+	mathutil.QuantileInterpolatedInvertedCDF,
+	mathutil.QuantileHazen,
+	mathutil.QuantileWeibull,
+	mathutil.QuantileLinear,
+	mathutil.QuantileMedianUnbiased,
+	mathutil.QuantileNormalUnbiased,
+}
+
+func TestQuantile(t *testing.T) {
+	const epsilon = 1e-12
+
+	vals := []float64{4, 2, 1, 3}
+
+	testCases := []struct {
+		testhelper.ID
+		q float64
+		// expVals gives the expected value for each of the quantileMethods
+		expVals []float64
+	}{
+		{
+			ID:      testhelper.MkID("q: 0"),
+			q:       0,
+			expVals: []float64{1, 1, 1, 1, 1, 1, 1, 1, 1},
+		},
+		{
+			ID: testhelper.MkID("q: 0.25"),
+			q:  0.25,
+			expVals: []float64{
+				1, 1.5, 1, 1, 1.5, 1.25, 1.75, 1.25 + 1.0/6.0, 1.4375,
+			},
+		},
+		{
+			ID:      testhelper.MkID("q: 0.5"),
+			q:       0.5,
+			expVals: []float64{2, 2.5, 2, 2, 2.5, 2.5, 2.5, 2.5, 2.5},
+		},
+		{
+			ID: testhelper.MkID("q: 0.75"),
+			q:  0.75,
+			expVals: []float64{
+				3, 3.5, 3, 3, 3.5, 3.75, 3.25, 3.5 + 1.0/12.0, 3.5625,
+			},
+		},
+		{
+			ID:      testhelper.MkID("q: 1"),
+			q:       1,
+			expVals: []float64{4, 4, 4, 4, 4, 4, 4, 4, 4},
+		},
+	}
+
+	for _, tc := range testCases {
+		for i, m := range quantileMethods {
+			orig := slices.Clone(vals)
+
+			v, err := mathutil.Quantile(vals, tc.q, m)
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: %s: unexpected error: %v", m, err)
+
+				continue
+			}
+
+			testhelper.DiffFloat(t, tc.IDStr(), m.String(),
+				v, tc.expVals[i], epsilon)
+			testhelper.DiffSlice(t, tc.IDStr(), m.String()+": vals changed",
+				vals, orig)
+
+			qs, err := mathutil.Quantiles(vals, m, tc.q)
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: %s: unexpected Quantiles error: %v", m, err)
+
+				continue
+			}
+
+			testhelper.DiffFloat(t, tc.IDStr(), m.String()+": Quantiles",
+				qs[0], tc.expVals[i], epsilon)
+		}
+	}
+}
+
+func TestQuantileErrs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		vals []float64
+		q    float64
+		testhelper.ExpErr
+	}{
+		{
+			ID:     testhelper.MkID("no values"),
+			q:      0.5,
+			ExpErr: testhelper.MkExpErr("there are no values"),
+		},
+		{
+			ID:     testhelper.MkID("NaN value"),
+			vals:   []float64{1, math.NaN(), 3},
+			q:      0.5,
+			ExpErr: testhelper.MkExpErr("the values include a NaN"),
+		},
+		{
+			ID:   testhelper.MkID("q too small"),
+			vals: []float64{1, 2, 3},
+			q:    -0.1,
+			ExpErr: testhelper.MkExpErr("bad quantile (-0.1)",
+				"it must be in the range [0, 1]"),
+		},
+		{
+			ID:   testhelper.MkID("q too big"),
+			vals: []float64{1, 2, 3},
+			q:    1.1,
+			ExpErr: testhelper.MkExpErr("bad quantile (1.1)",
+				"it must be in the range [0, 1]"),
+		},
+		{
+			ID:     testhelper.MkID("q is NaN"),
+			vals:   []float64{1, 2, 3},
+			q:      math.NaN(),
+			ExpErr: testhelper.MkExpErr("bad quantile (NaN)"),
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := mathutil.Quantile(tc.vals, tc.q, mathutil.QuantileLinear)
+		testhelper.CheckExpErr(t, err, tc)
+
+		_, err = mathutil.Quantiles(tc.vals, mathutil.QuantileLinear, tc.q)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+// TestQuantileSelect checks that the quickselect-based Quantile agrees with
+// the sort-based Quantiles for larger sets of values including duplicates.
+func TestQuantileSelect(t *testing.T) {
+	const epsilon = 1e-12
+
+	r := rand.New(rand.NewPCG(1, 2)) //nolint:gosec
+
+	for _, n := range []int{1, 2, 3, 10, 101, 1000} {
+		vals := make([]float64, 0, n)
+		for range n {
+			vals = append(vals, float64(r.IntN(n/2+1)))
+		}
+
+		for _, m := range quantileMethods {
+			for _, q := range []float64{0, 0.01, 0.1, 0.33, 0.5, 0.9, 0.999, 1} {
+				id := testhelper.MkID(
+					fmt.Sprintf("n: %d, q: %g, %s", n, q, m))
+
+				v, err := mathutil.Quantile(vals, q, m)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", id.IDStr(), err)
+				}
+
+				exp, err := mathutil.Quantiles(vals, m, q)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", id.IDStr(), err)
+				}
+
+				testhelper.DiffFloat(t, id.IDStr(), "quantile", v, exp[0],
+					epsilon)
+			}
+		}
+	}
+}
+
+// TestQuantileManyDuplicates checks Quantile on a large number of values
+// with few distinct values. A partition that does not group the values
+// equal to the pivot takes time quadratic in the number of values here.
+func TestQuantileManyDuplicates(t *testing.T) {
+	const n = 1_000_000
+
+	r := rand.New(rand.NewPCG(3, 4)) //nolint:gosec
+
+	lowCard := make([]float64, 0, n)
+	for range n {
+		lowCard = append(lowCard, float64(r.IntN(3)))
+	}
+
+	allSame := make([]float64, n)
+
+	for _, tc := range []struct {
+		name string
+		vals []float64
+	}{
+		{name: "values from {0, 1, 2}", vals: lowCard},
+		{name: "all the same", vals: allSame},
+	} {
+		for _, q := range []float64{0, 0.25, 0.5, 0.75, 1} {
+			id := fmt.Sprintf("%s, q: %g", tc.name, q)
+
+			v, err := mathutil.Quantile(tc.vals, q, mathutil.QuantileLinear)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", id, err)
+			}
+
+			exp, err := mathutil.Quantiles(tc.vals, mathutil.QuantileLinear, q)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", id, err)
+			}
+
+			testhelper.DiffFloat(t, id, "quantile", v, exp[0], 0)
+		}
+	}
+}
+
+func TestMedian(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		vals   []float64
+		expVal float64
+	}{
+		{
+			ID:     testhelper.MkID("one value"),
+			vals:   []float64{42},
+			expVal: 42,
+		},
+		{
+			ID:     testhelper.MkID("odd count"),
+			vals:   []float64{5, 1, 3},
+			expVal: 3,
+		},
+		{
+			ID:     testhelper.MkID("even count"),
+			vals:   []float64{5, 1, 3, 10},
+			expVal: 4,
+		},
+		{
+			ID:     testhelper.MkID("with infinities"),
+			vals:   []float64{math.Inf(1), 1, math.Inf(-1)},
+			expVal: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := mathutil.Median(tc.vals)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error: %v", err)
+
+			continue
+		}
+
+		testhelper.DiffFloat(t, tc.IDStr(), "median", v, tc.expVal, 0)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	const epsilon = 1e-12
+
+	vals := []float32{10, 20, 30, 40, 50}
+
+	v, err := mathutil.Percentile(vals, 25, mathutil.QuantileLinear)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffFloat(t, "Percentile", "25%", v, 20, epsilon)
+
+	pv, err := mathutil.Percentiles(vals, mathutil.QuantileLinear,
+		0, 10, 50, 90, 100)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffSlice(t, "Percentiles", "values",
+		pv, []float32{10, 14, 30, 46, 50})
+
+	_, err = mathutil.Percentile(vals, 101, mathutil.QuantileLinear)
+	if err == nil {
+		t.Error("an error was expected for a percentage over 100")
+	}
+}
+
+func TestQuantileMethodString(t *testing.T) {
+	testhelper.DiffString(t, "QuantileMethod", "known",
+		mathutil.QuantileHazen.String(), "QuantileHazen")
+	testhelper.DiffString(t, "QuantileMethod", "unknown",
+		mathutil.QuantileMethod(99).String(), "QuantileMethod(99)")
+}
@@ -159,8 +159,7 @@ func checkRationalTargetVal(v float64) error {
 		return errIsNaN
 	}
 
-	if v >= float64(math.MaxInt64) ||
-		v < float64(math.MinInt64) {
+	if _, err := Convert[int64](math.Trunc(v)); err != nil {
 		return errTooBig
 	}
 
@@ -221,19 +220,19 @@ func RationalApproximation(v, accuracy float64) (Rational, error) {
 		idxEnd--
 
 		for ; idxEnd >= 0; idxEnd-- {
-			if math.MaxInt64/r.D < cf[idxEnd] { // restart, ignoring end values
+			p, err := MulChecked(cf[idxEnd], r.D)
+			if err != nil { // restart, ignoring end values
 				r = Rational{N: 1, D: cf[idxEnd]}
 				continue
 			}
 
-			p := cf[idxEnd] * r.D
-
-			if math.MaxInt64-p < r.N { // restart, ignoring end values
+			n, err := AddChecked(r.N, p)
+			if err != nil { // restart, ignoring end values
 				r = Rational{N: 1, D: cf[idxEnd]}
 				continue
 			}
 
-			r.N += p
+			r.N = n
 			r = r.Invert()
 		}
 
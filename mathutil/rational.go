@@ -51,15 +51,17 @@ func (r Rational) Proximity(v float64) float64 {
 // does not check that the Mediant property holds, this is the responsibility
 // of the caller.
 func mediant(lower, upper Rational) (Rational, error) {
-	if math.MaxInt64-lower.N < upper.N {
+	n, err := AddChecked(lower.N, upper.N)
+	if err != nil {
 		return Rational{0, 1}, errNumeratorTooBig
 	}
 
-	if math.MaxInt64-lower.D < upper.D {
+	d, err := AddChecked(lower.D, upper.D)
+	if err != nil {
 		return Rational{0, 1}, errDenominatorTooBig
 	}
 
-	return Rational{N: lower.N + upper.N, D: lower.D + upper.D}, nil
+	return Rational{N: n, D: d}, nil
 }
 
 // SetRational constructs a Rational from the passed values, checks for
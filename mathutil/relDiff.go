@@ -0,0 +1,137 @@
+package mathutil
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// RelDiffBase determines the value that a difference is taken relative to
+type RelDiffBase int
+
+// These are the available RelDiffBase values. The magnitudes of the values
+// are used so the base is never negative.
+const (
+	// RelToMax gives the difference relative to the larger magnitude. This
+	// is the base used by WithinNPercent.
+	RelToMax RelDiffBase = iota
+	// RelToMin gives the difference relative to the smaller magnitude
+	RelToMin
+	// RelToA gives the difference relative to the first value
+	RelToA
+	// RelToB gives the difference relative to the second value
+	RelToB
+	// RelToMean gives the difference relative to the mean of the magnitudes
+	RelToMean
+)
+
+// String returns a string value for the RelDiffBase
+func (rdb RelDiffBase) String() string {
+	switch rdb {
+	case RelToMax:
+		return "RelToMax"
+	case RelToMin:
+		return "RelToMin"
+	case RelToA:
+		return "RelToA"
+	case RelToB:
+		return "RelToB"
+	case RelToMean:
+		return "RelToMean"
+	}
+
+	return fmt.Sprintf("RelDiffBase(%d)", int(rdb))
+}
+
+// base returns the value that the difference between a and b should be
+// taken relative to. It will panic if the RelDiffBase is not known.
+func (rdb RelDiffBase) base(a, b float64) float64 {
+	absA, absB := math.Abs(a), math.Abs(b)
+
+	switch rdb {
+	case RelToMax:
+		return math.Max(absA, absB)
+	case RelToMin:
+		return math.Min(absA, absB)
+	case RelToA:
+		return absA
+	case RelToB:
+		return absB
+	case RelToMean:
+		return (absA + absB) / 2 //nolint:mnd
+	}
+
+	panic(fmt.Sprintf("Invalid relative difference base: %s", rdb))
+}
+
+// RelDiff returns the absolute difference between a and b as a proportion
+// of the base value chosen by rdb. If the values are equal the result is
+// zero, otherwise if the base value is zero the result is +Inf.
+func RelDiff[F constraints.Float](a, b F, rdb RelDiffBase) F {
+	if a == b {
+		return 0
+	}
+
+	fa, fb := float64(a), float64(b)
+
+	return F(math.Abs(fa-fb) / rdb.base(fa, fb))
+}
+
+// SymmetricPctDiff returns the absolute difference between a and b as a
+// percentage of the mean of their magnitudes. The result is the same
+// whichever way round the values are given.
+func SymmetricPctDiff[F constraints.Float](a, b F) F {
+	return ToPercent(RelDiff(a, b, RelToMean))
+}
+
+// PctError returns the absolute difference between the measured value and
+// the reference value as a percentage of the magnitude of the reference
+// value.
+func PctError[F constraints.Float](measured, reference F) F {
+	return ToPercent(RelDiff(measured, reference, RelToB))
+}
+
+// LogRatio returns the natural logarithm of a/b. This is a symmetric
+// measure of the difference between two values; swapping the values only
+// changes the sign of the result. The result is NaN if the values have
+// differing signs and is infinite if either is zero.
+func LogRatio[F constraints.Float](a, b F) F {
+	return F(math.Log(float64(a) / float64(b)))
+}
+
+// ZeroStraddle determines how WithinNPercentOf treats values of differing
+// sign
+type ZeroStraddle int
+
+// These are the available ZeroStraddle values
+const (
+	// StraddleNeverClose treats values of differing sign as different
+	// regardless of proximity. This is how WithinNPercent behaves.
+	StraddleNeverClose ZeroStraddle = iota
+	// StraddleUseMetric applies the relative difference regardless of the
+	// signs of the values.
+	StraddleUseMetric
+)
+
+// WithinNPercentOf returns true if the difference between a and b as a
+// proportion of the base value chosen by rdb is no more than epsilon
+// percent. The treatment of values with differing signs is determined by
+// zs. The epsilon value is forced to a positive value (the absolute value is
+// taken).
+//
+// WithinNPercent(a, b, epsilon) is the same as
+// WithinNPercentOf(a, b, epsilon, RelToMax, StraddleNeverClose).
+func WithinNPercentOf[F constraints.Float](
+	a, b, epsilon F, rdb RelDiffBase, zs ZeroStraddle,
+) bool {
+	if a == b {
+		return true
+	}
+
+	if zs == StraddleNeverClose && (a < 0) != (b < 0) {
+		return false
+	}
+
+	return ToPercent(RelDiff(a, b, rdb)) <= F(math.Abs(float64(epsilon)))
+}
@@ -0,0 +1,160 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestRelDiff(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		a, b   float64
+		rdb    mathutil.RelDiffBase
+		expVal float64
+	}{
+		{
+			ID:     testhelper.MkID("equal"),
+			a:      2,
+			b:      2,
+			rdb:    mathutil.RelToMax,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("max"),
+			a:      80,
+			b:      100,
+			rdb:    mathutil.RelToMax,
+			expVal: 0.2,
+		},
+		{
+			ID:     testhelper.MkID("min"),
+			a:      80,
+			b:      100,
+			rdb:    mathutil.RelToMin,
+			expVal: 0.25,
+		},
+		{
+			ID:     testhelper.MkID("a"),
+			a:      80,
+			b:      100,
+			rdb:    mathutil.RelToA,
+			expVal: 0.25,
+		},
+		{
+			ID:     testhelper.MkID("b"),
+			a:      80,
+			b:      100,
+			rdb:    mathutil.RelToB,
+			expVal: 0.2,
+		},
+		{
+			ID:     testhelper.MkID("mean"),
+			a:      80,
+			b:      -120,
+			rdb:    mathutil.RelToMean,
+			expVal: 2,
+		},
+		{
+			ID:     testhelper.MkID("zero base"),
+			a:      0,
+			b:      1,
+			rdb:    mathutil.RelToMin,
+			expVal: math.Inf(1),
+		},
+		{
+			ID: testhelper.MkID("bad base"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid relative difference base: RelDiffBase(99)"),
+			a:   1,
+			b:   2,
+			rdb: mathutil.RelDiffBase(99),
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			v := mathutil.RelDiff(tc.a, tc.b, tc.rdb)
+			testhelper.DiffFloat(t, tc.IDStr(), "value", v, tc.expVal, 1e-12)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+func TestRelDiffMetrics(t *testing.T) {
+	const epsilon = 1e-12
+
+	testhelper.DiffFloat(t, "SymmetricPctDiff", "value",
+		mathutil.SymmetricPctDiff(90.0, 110.0), 20, epsilon)
+	testhelper.DiffFloat(t, "SymmetricPctDiff (reversed)", "value",
+		mathutil.SymmetricPctDiff(110.0, 90.0), 20, epsilon)
+	testhelper.DiffFloat(t, "PctError", "value",
+		mathutil.PctError(9.5, 10.0), 5, epsilon)
+	testhelper.DiffFloat(t, "LogRatio", "value",
+		mathutil.LogRatio(math.E, 1.0), 1, epsilon)
+	testhelper.DiffFloat(t, "LogRatio (reversed)", "value",
+		mathutil.LogRatio(1.0, math.E), -1, epsilon)
+	testhelper.DiffBool(t, "LogRatio (differing signs)", "is NaN",
+		math.IsNaN(mathutil.LogRatio(-1.0, 1.0)), true)
+}
+
+func TestWithinNPercentOf(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		a, b, pct float64
+		rdb       mathutil.RelDiffBase
+		zs        mathutil.ZeroStraddle
+		expResult bool
+	}{
+		{
+			ID:        testhelper.MkID("20% of max"),
+			a:         80,
+			b:         100,
+			pct:       20,
+			rdb:       mathutil.RelToMax,
+			expResult: true,
+		},
+		{
+			ID:        testhelper.MkID("20% of min"),
+			a:         80,
+			b:         100,
+			pct:       20,
+			rdb:       mathutil.RelToMin,
+			expResult: false,
+		},
+		{
+			ID:        testhelper.MkID("straddling zero, never close"),
+			a:         -1,
+			b:         1,
+			pct:       300,
+			rdb:       mathutil.RelToMax,
+			zs:        mathutil.StraddleNeverClose,
+			expResult: false,
+		},
+		{
+			ID:        testhelper.MkID("straddling zero, use metric"),
+			a:         -1,
+			b:         1,
+			pct:       300,
+			rdb:       mathutil.RelToMax,
+			zs:        mathutil.StraddleUseMetric,
+			expResult: true,
+		},
+		{
+			ID:        testhelper.MkID("identical"),
+			a:         1,
+			b:         1,
+			pct:       0,
+			rdb:       mathutil.RelToA,
+			expResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		res := mathutil.WithinNPercentOf(tc.a, tc.b, tc.pct, tc.rdb, tc.zs)
+		testhelper.DiffBool(t, tc.IDStr(), "result", res, tc.expResult)
+	}
+}
@@ -1,11 +1,100 @@
 package mathutil
 
 import (
+	"fmt"
 	"math"
+	"slices"
 
 	"golang.org/x/exp/constraints"
 )
 
+// defaultRoughlyFactors are the factors used by Roughly
+var defaultRoughlyFactors = []float64{100, 50, 10, 5}
+
+// roughlyGrid describes how the candidate values are chosen by RoughlyWith
+type roughlyGrid int
+
+const (
+	gridDecadeFactors roughlyGrid = iota
+	gridFactors
+	gridSeries
+)
+
+// roughlyCfg holds the configuration for RoughlyWith
+type roughlyCfg struct {
+	grid    roughlyGrid
+	factors []float64
+}
+
+// RoughlyOpt is an option that can be passed to RoughlyWith to change the
+// values it will round to.
+type RoughlyOpt func(*roughlyCfg)
+
+// checkRoughlyFactors will panic if there are no factors or if any of them
+// are not greater than zero. It returns a copy of the factors sorted into
+// descending order.
+func checkRoughlyFactors(factors []float64) []float64 {
+	if len(factors) == 0 {
+		panic("at least one factor must be given")
+	}
+
+	for _, f := range factors {
+		if !(f > 0) || math.IsInf(f, 0) {
+			panic(fmt.Sprintf(
+				"Invalid factor (%g), it must be finite and greater than zero",
+				f))
+		}
+	}
+
+	factors = slices.Clone(factors)
+	slices.Sort(factors)
+	slices.Reverse(factors)
+
+	return factors
+}
+
+// RoughlyFactors returns a RoughlyOpt which will make RoughlyWith round to a
+// multiple of one of the factors. The factors are in the same units as the
+// value so, for instance, factors of 15, 30 and 60 could be used to round a
+// duration in minutes to a quarter, a half or a whole hour. The largest
+// factor which keeps the value within the accuracy is used. It will panic if
+// no factors are given or if any of them are not greater than zero.
+func RoughlyFactors(factors ...float64) RoughlyOpt {
+	factors = checkRoughlyFactors(factors)
+
+	return func(rc *roughlyCfg) {
+		rc.grid = gridFactors
+		rc.factors = factors
+	}
+}
+
+// RoughlyDecadeFactors returns a RoughlyOpt which will make RoughlyWith
+// round to a multiple of one of the factors scaled by a power of ten. This
+// is how Roughly works and it uses the factors 100, 50, 10 and 5. So, for
+// instance, passing factors of 100, 25 and 10 will round values close to a
+// multiple of 25 at whatever scale the accuracy allows. The largest factor
+// which keeps the value within the accuracy is used. It will panic if no
+// factors are given or if any of them are not greater than zero.
+func RoughlyDecadeFactors(factors ...float64) RoughlyOpt {
+	factors = checkRoughlyFactors(factors)
+
+	return func(rc *roughlyCfg) {
+		rc.grid = gridDecadeFactors
+		rc.factors = factors
+	}
+}
+
+// RoughlySeries returns a RoughlyOpt which will make RoughlyWith round to
+// the nearest value in the given series of preferred numbers.
+func RoughlySeries(s PreferredSeries) RoughlyOpt {
+	vals := s.values()
+
+	return func(rc *roughlyCfg) {
+		rc.grid = gridSeries
+		rc.factors = vals
+	}
+}
+
 // trialRound will round v to the nearest multiple of factor
 func trialRound[F constraints.Float](v F, factor float64) F {
 	rounded := float64(v)
@@ -15,11 +104,79 @@ func trialRound[F constraints.Float](v F, factor float64) F {
 	return F(rounded * factor)
 }
 
+// roundToFactors returns the trial rounding of v for the first of the
+// factors which gives a value differing from v by less than maxDiff. If
+// none of them are close enough v is returned unchanged.
+func roundToFactors[F constraints.Float](v, maxDiff F, factors []float64) F {
+	for _, factor := range factors {
+		trial := trialRound(v, factor)
+		diff := math.Abs(float64(trial - v))
+
+		if F(diff) < maxDiff {
+			return trial
+		}
+	}
+
+	return v
+}
+
+// roundToDecadeFactors rounds v using the factors scaled by a power of ten
+// chosen so that the maximum difference lies between 10 and 100.
+func roundToDecadeFactors[F constraints.Float](
+	v, maxDiff F, factors []float64,
+) F {
+	precision := math.Floor(math.Log10(float64(maxDiff))) - 1
+	scale := math.Pow10(int(precision))
+	v /= F(scale)
+	maxDiff /= F(scale)
+
+	v = roundToFactors(v, maxDiff, factors)
+
+	return v * F(scale)
+}
+
+// roundToSeries rounds v to the nearest value in the series (scaled by a
+// power of ten). If the nearest value is not within maxDiff of v then v is
+// returned unchanged.
+func roundToSeries[F constraints.Float](v, maxDiff F, series []float64) F {
+	exp := int(math.Floor(math.Log10(float64(v))))
+	mantissa := float64(v) / math.Pow10(exp)
+
+	nearest := series[0] * base10 // the first value in the next decade
+	for _, s := range series {
+		if math.Abs(s-mantissa) < math.Abs(nearest-mantissa) {
+			nearest = s
+		}
+	}
+
+	const seriesSigFigs = 3
+
+	trial := RoundSigFigs(F(nearest*math.Pow10(exp)), seriesSigFigs,
+		RoundHalfEven)
+
+	if F(math.Abs(float64(trial-v))) < maxDiff {
+		return trial
+	}
+
+	return v
+}
+
 // Roughly converts v to a value that is "roughly" the same but closer to
 // some multiple of five or ten. It will never be more than accuracy percent
 // from the original value. The accuracy must be less than 100 and greater
 // than zero.
 func Roughly[F constraints.Float](v, accuracy F) F {
+	return RoughlyWith(v, accuracy)
+}
+
+// RoughlyWith converts v to a value that is "roughly" the same but closer to
+// some value chosen according to the options. With no options it behaves
+// exactly as Roughly. It will never be more than accuracy percent from the
+// original value; if no candidate value is close enough then v is returned
+// unchanged. The accuracy must be less than 100 and greater than zero.
+//
+// If more than one option is given the last one is used.
+func RoughlyWith[F constraints.Float](v, accuracy F, opts ...RoughlyOpt) F {
 	if v == 0 {
 		return v
 	}
@@ -28,6 +185,14 @@ func Roughly[F constraints.Float](v, accuracy F) F {
 		return v
 	}
 
+	rc := roughlyCfg{
+		grid:    gridDecadeFactors,
+		factors: defaultRoughlyFactors,
+	}
+	for _, o := range opts {
+		o(&rc)
+	}
+
 	accuracy = FromPercent(accuracy)
 
 	var signMult F = 1.0
@@ -36,24 +201,16 @@ func Roughly[F constraints.Float](v, accuracy F) F {
 	}
 
 	newV := v * F(signMult)
-
 	maxDiff := newV * accuracy
-	precision := math.Floor(math.Log10(float64(maxDiff))) - 1
-	scale := math.Pow10(int(precision))
-	newV /= F(scale)
-	maxDiff /= F(scale)
 
-	for _, factor := range []float64{100, 50, 10, 5} {
-		trial := trialRound(newV, factor)
-		diff := math.Abs(float64(trial - newV))
-
-		if F(diff) < maxDiff {
-			newV = trial
-			break
-		}
+	switch rc.grid {
+	case gridDecadeFactors:
+		newV = roundToDecadeFactors(newV, maxDiff, rc.factors)
+	case gridFactors:
+		newV = roundToFactors(newV, maxDiff, rc.factors)
+	case gridSeries:
+		newV = roundToSeries(newV, maxDiff, rc.factors)
 	}
 
-	newV *= F(scale)
-
 	return newV * signMult
 }
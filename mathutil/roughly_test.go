@@ -176,3 +176,128 @@ func TestRoughly(t *testing.T) {
 		testhelper.DiffFloat(t, tc.IDStr(), "", r, tc.expVal, tc.epsilon)
 	}
 }
+
+func TestRoughlyWith(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		v      float64
+		pct    float64
+		opts   []mathutil.RoughlyOpt
+		expVal float64
+	}{
+		{
+			ID:     testhelper.MkID("no options - same as Roughly"),
+			v:      123.456,
+			pct:    2.0,
+			expVal: 125.0,
+		},
+		{
+			ID:  testhelper.MkID("decade factors - round to x25"),
+			v:   1234.5,
+			pct: 2.0,
+			opts: []mathutil.RoughlyOpt{
+				mathutil.RoughlyDecadeFactors(25, 10),
+			},
+			expVal: 1225,
+		},
+		{
+			ID:     testhelper.MkID("factors - 15 minutes"),
+			v:      52,
+			pct:    20.0,
+			opts:   []mathutil.RoughlyOpt{mathutil.RoughlyFactors(15, 30, 60)},
+			expVal: 60,
+		},
+		{
+			ID:     testhelper.MkID("factors - 15 minutes, more accurate"),
+			v:      52,
+			pct:    15.0,
+			opts:   []mathutil.RoughlyOpt{mathutil.RoughlyFactors(15, 30, 60)},
+			expVal: 45,
+		},
+		{
+			ID:     testhelper.MkID("factors - none close enough"),
+			v:      52,
+			pct:    1.0,
+			opts:   []mathutil.RoughlyOpt{mathutil.RoughlyFactors(15, 30, 60)},
+			expVal: 52,
+		},
+		{
+			ID:  testhelper.MkID("series - E12"),
+			v:   4600,
+			pct: 5.0,
+			opts: []mathutil.RoughlyOpt{
+				mathutil.RoughlySeries(mathutil.SeriesE12),
+			},
+			expVal: 4700,
+		},
+		{
+			ID:  testhelper.MkID("series - E12, negative"),
+			v:   -0.0046,
+			pct: 5.0,
+			opts: []mathutil.RoughlyOpt{
+				mathutil.RoughlySeries(mathutil.SeriesE12),
+			},
+			expVal: -0.0047,
+		},
+		{
+			ID:  testhelper.MkID("series - E12, next decade"),
+			v:   9.5,
+			pct: 10.0,
+			opts: []mathutil.RoughlyOpt{
+				mathutil.RoughlySeries(mathutil.SeriesE12),
+			},
+			expVal: 10,
+		},
+		{
+			ID:  testhelper.MkID("series - E6, not close enough"),
+			v:   2.7,
+			pct: 5.0,
+			opts: []mathutil.RoughlyOpt{
+				mathutil.RoughlySeries(mathutil.SeriesE6),
+			},
+			expVal: 2.7,
+		},
+		{
+			ID:  testhelper.MkID("last option wins"),
+			v:   52,
+			pct: 20.0,
+			opts: []mathutil.RoughlyOpt{
+				mathutil.RoughlySeries(mathutil.SeriesE6),
+				mathutil.RoughlyFactors(15, 30, 60),
+			},
+			expVal: 60,
+		},
+	}
+
+	for _, tc := range testCases {
+		r := mathutil.RoughlyWith(tc.v, tc.pct, tc.opts...)
+		testhelper.DiffFloat(t, tc.IDStr(), "", r, tc.expVal, 1e-9)
+	}
+}
+
+func TestRoughlyFactorsPanic(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		factors []float64
+	}{
+		{
+			ID: testhelper.MkID("no factors"),
+			ExpPanic: testhelper.MkExpPanic(
+				"at least one factor must be given"),
+		},
+		{
+			ID: testhelper.MkID("zero factor"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid factor (0), it must be finite and greater than zero"),
+			factors: []float64{10, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			mathutil.RoughlyFactors(tc.factors...)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
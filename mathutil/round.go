@@ -0,0 +1,293 @@
+package mathutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// RoundingMode determines how a value is rounded when it lies between two
+// candidate values.
+type RoundingMode int
+
+// These are the available rounding modes. The "half" modes only differ in
+// how they treat values lying exactly halfway between the two candidates.
+const (
+	// RoundHalfEven rounds to the nearest value; ties go to the even
+	// neighbour. This is also known as banker's rounding.
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest value; ties go away from zero.
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest value; ties go towards zero.
+	RoundHalfDown
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundTowardZero truncates the value.
+	RoundTowardZero
+	// RoundAwayFromZero rounds up the magnitude of the value.
+	RoundAwayFromZero
+)
+
+// String returns a string value for the RoundingMode
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundHalfEven:
+		return "RoundHalfEven"
+	case RoundHalfUp:
+		return "RoundHalfUp"
+	case RoundHalfDown:
+		return "RoundHalfDown"
+	case RoundCeiling:
+		return "RoundCeiling"
+	case RoundFloor:
+		return "RoundFloor"
+	case RoundTowardZero:
+		return "RoundTowardZero"
+	case RoundAwayFromZero:
+		return "RoundAwayFromZero"
+	}
+
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// roundsAway reports whether a value being rounded should be rounded away
+// from zero (rather than truncated). The discarded part of the value is
+// non-zero; above and half report whether it is more than or exactly half
+// of the unit being rounded to, neg whether the value is negative and odd
+// whether the last retained digit is odd. It will panic if the mode is not
+// a known RoundingMode.
+func (m RoundingMode) roundsAway(neg, above, half, odd bool) bool {
+	switch m {
+	case RoundHalfEven:
+		return above || (half && odd)
+	case RoundHalfUp:
+		return above || half
+	case RoundHalfDown:
+		return above
+	case RoundCeiling:
+		return !neg
+	case RoundFloor:
+		return neg
+	case RoundTowardZero:
+		return false
+	case RoundAwayFromZero:
+		return true
+	}
+
+	panic(fmt.Sprintf("Invalid rounding mode: %s", m))
+}
+
+// decimalVal holds a decimal representation of a float. The value is
+// 0.digits * 10^dp; the digits have no leading or trailing zeros.
+type decimalVal struct {
+	neg    bool
+	digits []byte
+	dp     int
+}
+
+// floatBitSize returns the size in bits of the float type (32 or 64)
+func floatBitSize[F constraints.Float]() int {
+	return BitsOf[F]()
+}
+
+// mkDecimalVal returns the shortest decimal representation of v that will
+// convert back to the same value. The value must be finite and non-zero.
+func mkDecimalVal[F constraints.Float](v F) decimalVal {
+	s := strconv.FormatFloat(float64(v), 'e', -1, floatBitSize[F]())
+
+	var d decimalVal
+	if s[0] == '-' {
+		d.neg = true
+		s = s[1:]
+	}
+
+	mantissa, expStr, _ := strings.Cut(s, "e")
+	exp, _ := strconv.Atoi(expStr)
+
+	d.digits = []byte(strings.Replace(mantissa, ".", "", 1))
+	d.dp = exp + 1
+
+	return d
+}
+
+// roundUpMagnitude reports whether the kept digits should be incremented
+// given the rounding mode and the digits being discarded.
+func (d decimalVal) roundUpMagnitude(keep int, mode RoundingMode) bool {
+	discarded := d.digits[keep:]
+	if len(discarded) == 0 {
+		return false
+	}
+
+	lastKeptIsOdd := keep > 0 && (d.digits[keep-1]-'0')%2 == 1
+
+	above, half := false, false
+
+	switch first := discarded[0]; {
+	case first > '5':
+		above = true
+	case first == '5':
+		if strings.Trim(string(discarded[1:]), "0") == "" {
+			half = true
+		} else {
+			above = true
+		}
+	}
+
+	return mode.roundsAway(d.neg, above, half, lastKeptIsOdd)
+}
+
+// round returns the decimal value rounded so that only the first keep
+// digits are retained. A keep value less than zero means that the rounding
+// is to a position above the first significant digit.
+func (d decimalVal) round(keep int, mode RoundingMode) decimalVal {
+	if keep < 0 {
+		// the value is less than a tenth of the unit being rounded to,
+		// 10^(dp-keep), so the result is either zero or one unit
+		if !mode.roundsAway(d.neg, false, false, false) {
+			d.digits = nil
+			return d
+		}
+
+		d.digits = []byte{'1'}
+		d.dp -= keep - 1
+
+		return d
+	}
+
+	if keep >= len(d.digits) {
+		return d
+	}
+
+	up := d.roundUpMagnitude(keep, mode)
+
+	d.digits = d.digits[:keep:keep]
+	if !up {
+		return d
+	}
+
+	i := len(d.digits) - 1
+	for ; i >= 0; i-- {
+		if d.digits[i] != '9' {
+			d.digits[i]++
+			break
+		}
+
+		d.digits[i] = '0'
+	}
+
+	if i < 0 {
+		d.digits = append([]byte{'1'}, d.digits...)
+		d.dp++
+	}
+
+	return d
+}
+
+// toFloat converts the decimal value back to a float. Note that if rounding
+// has made the value too large for the type it will return an infinite
+// value.
+func toFloat[F constraints.Float](d decimalVal, bitSize int) F {
+	if len(d.digits) == 0 {
+		if d.neg {
+			return F(math.Copysign(0, -1))
+		}
+
+		return 0
+	}
+
+	s := "0." + string(d.digits) + "e" + strconv.Itoa(d.dp)
+	if d.neg {
+		s = "-" + s
+	}
+
+	f, _ := strconv.ParseFloat(s, bitSize)
+
+	return F(f)
+}
+
+// RoundSigFigs returns v rounded to sf significant figures using the given
+// rounding mode. The rounding is performed on the shortest decimal
+// representation of v so it is free of the artefacts that come from scaling
+// a float by a power of ten; for instance 2.675 rounded to 3 significant
+// figures with RoundHalfUp gives 2.68. Note that sf must be greater than 0,
+// a panic is generated if not.
+//
+// Infinite values, NaNs and zero are returned unchanged.
+func RoundSigFigs[F constraints.Float](v F, sf uint8, mode RoundingMode) F {
+	if sf == 0 {
+		panic("the number of significant figures must be greater than zero")
+	}
+
+	if v == 0 || math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+		return v
+	}
+
+	return toFloat[F](mkDecimalVal(v).round(int(sf), mode), floatBitSize[F]())
+}
+
+// RoundPlaces returns v rounded to the given number of decimal places using
+// the given rounding mode. A negative number of places will round to the
+// left of the decimal point, so -2 rounds to a multiple of 100. As with
+// RoundSigFigs the rounding is performed on the decimal representation of v.
+//
+// Infinite values, NaNs and zero are returned unchanged.
+func RoundPlaces[F constraints.Float](v F, places int, mode RoundingMode) F {
+	if v == 0 || math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+		return v
+	}
+
+	// any number of places beyond this either changes nothing or rounds
+	// to zero or an infinite value; limiting it avoids integer overflow
+	const maxPlaces = 1000
+
+	places = min(max(places, -maxPlaces), maxPlaces)
+
+	d := mkDecimalVal(v)
+
+	return toFloat[F](d.round(d.dp+places, mode), floatBitSize[F]())
+}
+
+// absInt returns the absolute value of v
+func absInt[I constraints.Integer](v I) I {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// DivRound returns n divided by d rounded to a whole number using the given
+// rounding mode, rather than truncated as the division operator would. It
+// will panic if d is zero.
+func DivRound[I constraints.Integer](n, d I, mode RoundingMode) I {
+	q, r := n/d, n%d
+	if r == 0 {
+		return q
+	}
+
+	neg := (n < 0) != (d < 0)
+
+	var other I // the distance from the remainder to the divisor
+	if (r < 0) == (d < 0) {
+		other = d - r
+	} else {
+		other = d + r
+	}
+
+	absR, absOther := absInt(r), absInt(other)
+
+	if mode.roundsAway(neg, absR > absOther, absR == absOther, q%2 != 0) {
+		if neg {
+			q--
+		} else {
+			q++
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,388 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestRoundSigFigs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		v      float64
+		sf     uint8
+		mode   mathutil.RoundingMode
+		expVal float64
+	}{
+		{
+			ID: testhelper.MkID("bad sig figs"),
+			ExpPanic: testhelper.MkExpPanic(
+				"the number of significant figures must be greater than zero"),
+			v:  1.0,
+			sf: 0,
+		},
+		{
+			ID:     testhelper.MkID("zero"),
+			v:      0,
+			sf:     3,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("fewer digits than sig figs"),
+			v:      1.5,
+			sf:     3,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 1.5,
+		},
+		{
+			ID:     testhelper.MkID("2.675, half up"),
+			v:      2.675,
+			sf:     3,
+			mode:   mathutil.RoundHalfUp,
+			expVal: 2.68,
+		},
+		{
+			ID:     testhelper.MkID("2.675, half even"),
+			v:      2.675,
+			sf:     3,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 2.68,
+		},
+		{
+			ID:     testhelper.MkID("2.665, half even"),
+			v:      2.665,
+			sf:     3,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 2.66,
+		},
+		{
+			ID:     testhelper.MkID("2.665, half down"),
+			v:      2.665,
+			sf:     3,
+			mode:   mathutil.RoundHalfDown,
+			expVal: 2.66,
+		},
+		{
+			ID:     testhelper.MkID("2.6651, half down"),
+			v:      2.6651,
+			sf:     3,
+			mode:   mathutil.RoundHalfDown,
+			expVal: 2.67,
+		},
+		{
+			ID:     testhelper.MkID("-2.675, half up"),
+			v:      -2.675,
+			sf:     3,
+			mode:   mathutil.RoundHalfUp,
+			expVal: -2.68,
+		},
+		{
+			ID:     testhelper.MkID("12345, ceiling"),
+			v:      12345,
+			sf:     2,
+			mode:   mathutil.RoundCeiling,
+			expVal: 13000,
+		},
+		{
+			ID:     testhelper.MkID("-12345, ceiling"),
+			v:      -12345,
+			sf:     2,
+			mode:   mathutil.RoundCeiling,
+			expVal: -12000,
+		},
+		{
+			ID:     testhelper.MkID("12345, floor"),
+			v:      12345,
+			sf:     2,
+			mode:   mathutil.RoundFloor,
+			expVal: 12000,
+		},
+		{
+			ID:     testhelper.MkID("-12345, floor"),
+			v:      -12345,
+			sf:     2,
+			mode:   mathutil.RoundFloor,
+			expVal: -13000,
+		},
+		{
+			ID:     testhelper.MkID("-12345, toward zero"),
+			v:      -12345,
+			sf:     2,
+			mode:   mathutil.RoundTowardZero,
+			expVal: -12000,
+		},
+		{
+			ID:     testhelper.MkID("-12345, away from zero"),
+			v:      -12345,
+			sf:     2,
+			mode:   mathutil.RoundAwayFromZero,
+			expVal: -13000,
+		},
+		{
+			ID:     testhelper.MkID("carry into a new digit"),
+			v:      0.000999,
+			sf:     2,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 0.001,
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			v := mathutil.RoundSigFigs(tc.v, tc.sf, tc.mode)
+			testhelper.DiffFloat(t, tc.IDStr(), "rounded value",
+				v, tc.expVal, 0)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+func TestRoundPlaces(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		v      float64
+		places int
+		mode   mathutil.RoundingMode
+		expVal float64
+	}{
+		{
+			ID:     testhelper.MkID("1.005, 2dp, half up"),
+			v:      1.005,
+			places: 2,
+			mode:   mathutil.RoundHalfUp,
+			expVal: 1.01,
+		},
+		{
+			ID:     testhelper.MkID("0.125, 2dp, half even"),
+			v:      0.125,
+			places: 2,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 0.12,
+		},
+		{
+			ID:     testhelper.MkID("0.135, 2dp, half even"),
+			v:      0.135,
+			places: 2,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 0.14,
+		},
+		{
+			ID:     testhelper.MkID("1234.5, -2dp, half up"),
+			v:      1234.5,
+			places: -2,
+			mode:   mathutil.RoundHalfUp,
+			expVal: 1200,
+		},
+		{
+			ID:     testhelper.MkID("0.004, 2dp, half up"),
+			v:      0.004,
+			places: 2,
+			mode:   mathutil.RoundHalfUp,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("0.004, 2dp, ceiling"),
+			v:      0.004,
+			places: 2,
+			mode:   mathutil.RoundCeiling,
+			expVal: 0.01,
+		},
+		{
+			ID:     testhelper.MkID("-0.0004, 2dp, away from zero"),
+			v:      -0.0004,
+			places: 2,
+			mode:   mathutil.RoundAwayFromZero,
+			expVal: -0.01,
+		},
+		{
+			ID:     testhelper.MkID("999.9, 0dp, half even"),
+			v:      999.9,
+			places: 0,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 1000,
+		},
+		{
+			ID:     testhelper.MkID("2.5, 0dp, half even"),
+			v:      2.5,
+			places: 0,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 2,
+		},
+		{
+			ID:     testhelper.MkID("1.5, MaxInt dp"),
+			v:      1.5,
+			places: math.MaxInt,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 1.5,
+		},
+		{
+			ID:     testhelper.MkID("1.5, MinInt dp, half even"),
+			v:      1.5,
+			places: math.MinInt,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("1.5, MinInt dp, ceiling"),
+			v:      1.5,
+			places: math.MinInt,
+			mode:   mathutil.RoundCeiling,
+			expVal: math.Inf(1),
+		},
+		{
+			ID:     testhelper.MkID("-1.5, MinInt dp, floor"),
+			v:      -1.5,
+			places: math.MinInt,
+			mode:   mathutil.RoundFloor,
+			expVal: math.Inf(-1),
+		},
+		{
+			ID:     testhelper.MkID("1234.5, -5dp, half up"),
+			v:      1234.5,
+			places: -5,
+			mode:   mathutil.RoundHalfUp,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("1234.5, -5dp, away from zero"),
+			v:      1234.5,
+			places: -5,
+			mode:   mathutil.RoundAwayFromZero,
+			expVal: 100000,
+		},
+		{
+			ID:     testhelper.MkID("-1234.5, -5dp, ceiling"),
+			v:      -1234.5,
+			places: -5,
+			mode:   mathutil.RoundCeiling,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("-1234.5, -5dp, floor"),
+			v:      -1234.5,
+			places: -5,
+			mode:   mathutil.RoundFloor,
+			expVal: -100000,
+		},
+		{
+			ID:     testhelper.MkID("1e-300, 2dp, ceiling"),
+			v:      1e-300,
+			places: 2,
+			mode:   mathutil.RoundCeiling,
+			expVal: 0.01,
+		},
+		{
+			ID:     testhelper.MkID("1e300, -1000000dp, half even"),
+			v:      1e300,
+			places: -1000000,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("infinity is unchanged"),
+			v:      math.Inf(1),
+			places: 2,
+			mode:   mathutil.RoundHalfEven,
+			expVal: math.Inf(1),
+		},
+	}
+
+	for _, tc := range testCases {
+		v := mathutil.RoundPlaces(tc.v, tc.places, tc.mode)
+		testhelper.DiffFloat(t, tc.IDStr(), "rounded value", v, tc.expVal, 0)
+	}
+}
+
+func TestRoundFloat32(t *testing.T) {
+	v := mathutil.RoundPlaces(float32(1.005), 2, mathutil.RoundHalfUp)
+	testhelper.DiffFloat(t, "float32: 1.005, 2dp", "rounded value",
+		v, float32(1.01), 0)
+}
+
+func TestDivRound(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		n, d   int8
+		mode   mathutil.RoundingMode
+		expVal int8
+	}{
+		{
+			ID:     testhelper.MkID("exact"),
+			n:      10,
+			d:      5,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 2,
+		},
+		{
+			ID:     testhelper.MkID("7/2, half even"),
+			n:      7,
+			d:      2,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 4,
+		},
+		{
+			ID:     testhelper.MkID("5/2, half even"),
+			n:      5,
+			d:      2,
+			mode:   mathutil.RoundHalfEven,
+			expVal: 2,
+		},
+		{
+			ID:     testhelper.MkID("-5/2, half up"),
+			n:      -5,
+			d:      2,
+			mode:   mathutil.RoundHalfUp,
+			expVal: -3,
+		},
+		{
+			ID:     testhelper.MkID("5/-2, half down"),
+			n:      5,
+			d:      -2,
+			mode:   mathutil.RoundHalfDown,
+			expVal: -2,
+		},
+		{
+			ID:     testhelper.MkID("-7/3, floor"),
+			n:      -7,
+			d:      3,
+			mode:   mathutil.RoundFloor,
+			expVal: -3,
+		},
+		{
+			ID:     testhelper.MkID("-7/3, ceiling"),
+			n:      -7,
+			d:      3,
+			mode:   mathutil.RoundCeiling,
+			expVal: -2,
+		},
+		{
+			ID:     testhelper.MkID("-128/-128, ceiling"),
+			n:      -128,
+			d:      -128,
+			mode:   mathutil.RoundCeiling,
+			expVal: 1,
+		},
+		{
+			ID:     testhelper.MkID("127/-128, half even"),
+			n:      127,
+			d:      -128,
+			mode:   mathutil.RoundHalfEven,
+			expVal: -1,
+		},
+		{
+			ID:     testhelper.MkID("-128/127, toward zero"),
+			n:      -128,
+			d:      127,
+			mode:   mathutil.RoundTowardZero,
+			expVal: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		v := mathutil.DivRound(tc.n, tc.d, tc.mode)
+		testhelper.DiffInt(t, tc.IDStr(), "value", v, tc.expVal)
+	}
+}
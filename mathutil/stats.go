@@ -0,0 +1,267 @@
+package mathutil
+
+import (
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Stats accumulates running statistics over a stream of values. The values
+// are added one at a time and the statistics are available at any point;
+// the values themselves are not retained. The moments are updated using the
+// numerically stable online algorithms of Welford and Terriberry and two
+// Stats can be merged (for instance after accumulating values in parallel)
+// using the formulae of Chan et al. and Pébay.
+//
+// NaN values are treated according to the NaNPolicy given to NewStats
+// (the zero Stats value uses NaNPropagate). With NaNPropagate, once a NaN
+// has been added all the statistics are NaN. With NaNIgnore, NaNs are
+// counted but otherwise ignored. With NaNLowest (or NaNHighest), NaNs are
+// excluded from the moments but the Min (or Max) is NaN, in keeping with
+// MinOfOK and MaxOfOK.
+type Stats[F constraints.Float] struct {
+	nanPolicy NaNPolicy
+	n         int64
+	nanCount  int64
+	sum       float64
+	mean      float64
+	m2        float64
+	m3        float64
+	m4        float64
+	minVal    F
+	maxVal    F
+}
+
+// NewStats returns a pointer to a new Stats which will treat NaN values
+// according to the NaNPolicy
+func NewStats[F constraints.Float](p NaNPolicy) *Stats[F] {
+	p.nanWins(false) // panics if the policy is invalid
+
+	return &Stats[F]{nanPolicy: p}
+}
+
+// Add adds the value to the statistics
+func (s *Stats[F]) Add(v F) {
+	x := float64(v)
+	if math.IsNaN(x) {
+		s.nanCount++
+		return
+	}
+
+	if s.n == 0 {
+		s.minVal, s.maxVal = v, v
+	} else {
+		s.minVal = min(s.minVal, v)
+		s.maxVal = max(s.maxVal, v)
+	}
+
+	n1 := float64(s.n)
+	s.n++
+	n := float64(s.n)
+
+	delta := x - s.mean
+	deltaN := delta / n
+	deltaN2 := deltaN * deltaN
+	term1 := delta * deltaN * n1
+
+	s.sum += x
+	s.mean += deltaN
+	s.m4 += term1*deltaN2*(n*n-3*n+3) + 6*deltaN2*s.m2 - 4*deltaN*s.m3
+	s.m3 += term1*deltaN*(n-2) - 3*deltaN*s.m2
+	s.m2 += term1
+}
+
+// AddAll adds each of the values to the statistics
+func (s *Stats[F]) AddAll(vals ...F) {
+	for _, v := range vals {
+		s.Add(v)
+	}
+}
+
+// Remove removes a value, previously added, from the statistics. Note that
+// the Min and Max cannot be recovered and so are not changed; they remain
+// bounds on the remaining values. Removing a value which was never added
+// gives meaningless results.
+func (s *Stats[F]) Remove(v F) {
+	x := float64(v)
+	if math.IsNaN(x) {
+		if s.nanCount > 0 {
+			s.nanCount--
+		}
+
+		return
+	}
+
+	if s.n <= 1 {
+		s.n, s.sum, s.mean, s.m2, s.m3, s.m4 = 0, 0, 0, 0, 0, 0
+		return
+	}
+
+	n := float64(s.n)
+	na := n - 1
+
+	mean := (n*s.mean - x) / na
+	delta := x - mean
+	d2 := delta * delta
+	d3 := d2 * delta
+	d4 := d2 * d2
+
+	m2 := s.m2 - d2*na/n
+	m3 := s.m3 - d3*na*(na-1)/(n*n) + 3*delta*m2/n
+	m4 := s.m4 - d4*na*(na*na-na+1)/(n*n*n) -
+		6*d2*m2/(n*n) + 4*delta*m3/n
+
+	s.n--
+	s.sum -= x
+	s.mean, s.m2, s.m3, s.m4 = mean, m2, m3, m4
+}
+
+// Merge adds the statistics from other into s. The NaN policy of s is
+// retained.
+func (s *Stats[F]) Merge(other *Stats[F]) {
+	s.nanCount += other.nanCount
+
+	if other.n == 0 {
+		return
+	}
+
+	if s.n == 0 {
+		p, nanCount := s.nanPolicy, s.nanCount
+		*s = *other
+		s.nanPolicy, s.nanCount = p, nanCount
+
+		return
+	}
+
+	na, nb := float64(s.n), float64(other.n)
+	n := na + nb
+
+	delta := other.mean - s.mean
+	d2 := delta * delta
+	d3 := d2 * delta
+	d4 := d2 * d2
+
+	m2 := s.m2 + other.m2 + d2*na*nb/n
+	m3 := s.m3 + other.m3 + d3*na*nb*(na-nb)/(n*n) +
+		3*delta*(na*other.m2-nb*s.m2)/n
+	m4 := s.m4 + other.m4 + d4*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*d2*(na*na*other.m2+nb*nb*s.m2)/(n*n) +
+		4*delta*(na*other.m3-nb*s.m3)/n
+
+	s.mean += delta * nb / n
+	s.m2, s.m3, s.m4 = m2, m3, m4
+	s.n += other.n
+	s.sum += other.sum
+	s.minVal = min(s.minVal, other.minVal)
+	s.maxVal = max(s.maxVal, other.maxVal)
+}
+
+// nanResult returns true if the statistics should be reported as NaN
+func (s *Stats[F]) nanResult() bool {
+	return s.nanCount > 0 && s.nanPolicy == NaNPropagate
+}
+
+// Count returns the number of values contributing to the statistics; NaNs
+// are not counted.
+func (s *Stats[F]) Count() int64 {
+	return s.n
+}
+
+// NaNCount returns the number of NaN values that have been added
+func (s *Stats[F]) NaNCount() int64 {
+	return s.nanCount
+}
+
+// Sum returns the sum of the values
+func (s *Stats[F]) Sum() F {
+	if s.nanResult() {
+		return F(math.NaN())
+	}
+
+	return F(s.sum)
+}
+
+// Mean returns the arithmetic mean of the values. It is NaN if there are
+// no values.
+func (s *Stats[F]) Mean() F {
+	if s.nanResult() || s.n == 0 {
+		return F(math.NaN())
+	}
+
+	return F(s.mean)
+}
+
+// Variance returns the sample variance of the values (the sum of squared
+// differences from the mean divided by one less than the count). It is NaN
+// if there are fewer than two values.
+func (s *Stats[F]) Variance() F {
+	if s.nanResult() || s.n < 2 {
+		return F(math.NaN())
+	}
+
+	return F(s.m2 / float64(s.n-1))
+}
+
+// PopVariance returns the population variance of the values (the sum of
+// squared differences from the mean divided by the count). It is NaN if
+// there are no values.
+func (s *Stats[F]) PopVariance() F {
+	if s.nanResult() || s.n == 0 {
+		return F(math.NaN())
+	}
+
+	return F(s.m2 / float64(s.n))
+}
+
+// StdDev returns the sample standard deviation of the values
+func (s *Stats[F]) StdDev() F {
+	return F(math.Sqrt(float64(s.Variance())))
+}
+
+// PopStdDev returns the population standard deviation of the values
+func (s *Stats[F]) PopStdDev() F {
+	return F(math.Sqrt(float64(s.PopVariance())))
+}
+
+// Skewness returns the (population) skewness of the values. It is NaN if
+// there are no values or if they are all the same.
+func (s *Stats[F]) Skewness() F {
+	if s.nanResult() || s.n == 0 || s.m2 == 0 {
+		return F(math.NaN())
+	}
+
+	return F(math.Sqrt(float64(s.n)) * s.m3 / math.Pow(s.m2, 1.5))
+}
+
+// Kurtosis returns the (population) excess kurtosis of the values; that
+// is, the kurtosis minus 3 so that a normal distribution has a value of
+// zero. It is NaN if there are no values or if they are all the same.
+func (s *Stats[F]) Kurtosis() F {
+	if s.nanResult() || s.n == 0 || s.m2 == 0 {
+		return F(math.NaN())
+	}
+
+	return F(float64(s.n)*s.m4/(s.m2*s.m2) - 3)
+}
+
+// Min returns the least of the values added and true. If no values have
+// been added it returns false. NaNs are treated according to the NaNPolicy.
+func (s *Stats[F]) Min() (F, bool) {
+	if s.nanCount > 0 &&
+		(s.nanPolicy.nanWins(false) || (s.n == 0 && s.nanPolicy != NaNIgnore)) {
+		return F(math.NaN()), true
+	}
+
+	return s.minVal, s.n > 0
+}
+
+// Max returns the greatest of the values added and true. If no values have
+// been added it returns false. NaNs are treated according to the NaNPolicy.
+func (s *Stats[F]) Max() (F, bool) {
+	if s.nanCount > 0 &&
+		(s.nanPolicy.nanWins(true) || (s.n == 0 && s.nanPolicy != NaNIgnore)) {
+		return F(math.NaN()), true
+	}
+
+	return s.maxVal, s.n > 0
+}
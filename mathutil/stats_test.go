@@ -0,0 +1,197 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// statsVals holds the expected statistics for a Stats
+type statsVals struct {
+	count       int64
+	sum         float64
+	mean        float64
+	variance    float64
+	popVariance float64
+	skewness    float64
+	kurtosis    float64
+	minVal      float64
+	maxVal      float64
+}
+
+// diffStats compares the statistics from s against the expected values
+func diffStats(t *testing.T, id string, s *mathutil.Stats[float64],
+	exp statsVals,
+) {
+	t.Helper()
+
+	const epsilon = 1e-9
+
+	testhelper.DiffInt(t, id, "count", s.Count(), exp.count)
+	testhelper.DiffFloat(t, id, "sum", s.Sum(), exp.sum, epsilon)
+	testhelper.DiffFloat(t, id, "mean", s.Mean(), exp.mean, epsilon)
+	testhelper.DiffFloat(t, id, "variance", s.Variance(), exp.variance,
+		epsilon)
+	testhelper.DiffFloat(t, id, "pop variance", s.PopVariance(),
+		exp.popVariance, epsilon)
+	testhelper.DiffFloat(t, id, "std dev", s.StdDev(),
+		math.Sqrt(exp.variance), epsilon)
+	testhelper.DiffFloat(t, id, "pop std dev", s.PopStdDev(),
+		math.Sqrt(exp.popVariance), epsilon)
+	testhelper.DiffFloat(t, id, "skewness", s.Skewness(), exp.skewness,
+		epsilon)
+	testhelper.DiffFloat(t, id, "kurtosis", s.Kurtosis(), exp.kurtosis,
+		epsilon)
+
+	minVal, ok := s.Min()
+	testhelper.DiffBool(t, id, "min ok", ok, true)
+	testhelper.DiffFloat(t, id, "min", minVal, exp.minVal, 0)
+
+	maxVal, ok := s.Max()
+	testhelper.DiffBool(t, id, "max ok", ok, true)
+	testhelper.DiffFloat(t, id, "max", maxVal, exp.maxVal, 0)
+}
+
+// expStats calculates the expected statistics directly from the values
+func expStats(vals []float64) statsVals {
+	sv := statsVals{
+		count:  int64(len(vals)),
+		minVal: mathutil.MinOf(vals...),
+		maxVal: mathutil.MaxOf(vals...),
+	}
+
+	for _, v := range vals {
+		sv.sum += v
+	}
+
+	n := float64(len(vals))
+	sv.mean = sv.sum / n
+
+	var m2, m3, m4 float64
+
+	for _, v := range vals {
+		d := v - sv.mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+
+	sv.variance = m2 / (n - 1)
+	sv.popVariance = m2 / n
+	sv.skewness = math.Sqrt(n) * m3 / math.Pow(m2, 1.5)
+	sv.kurtosis = n*m4/(m2*m2) - 3
+
+	return sv
+}
+
+func TestStats(t *testing.T) {
+	vals := []float64{2, 4, 4, 4, 5, 5, 7, 9, 1.5, -3}
+
+	var s mathutil.Stats[float64]
+
+	s.AddAll(vals...)
+	diffStats(t, "all values", &s, expStats(vals))
+
+	a := mathutil.NewStats[float64](mathutil.NaNPropagate)
+	a.AddAll(vals[:3]...)
+
+	b := mathutil.NewStats[float64](mathutil.NaNPropagate)
+	b.AddAll(vals[3:]...)
+
+	a.Merge(b)
+	diffStats(t, "merged", a, expStats(vals))
+
+	var empty mathutil.Stats[float64]
+
+	empty.Merge(a)
+	diffStats(t, "merged into empty", &empty, expStats(vals))
+
+	s.Remove(vals[0])
+	s.Remove(vals[1])
+
+	exp := expStats(vals[2:])
+	exp.minVal = -3 // the min and max are not changed by Remove
+	exp.maxVal = 9
+	diffStats(t, "removed", &s, exp)
+}
+
+func TestStatsEmpty(t *testing.T) {
+	var s mathutil.Stats[float32]
+
+	testhelper.DiffInt(t, "empty", "count", s.Count(), 0)
+	testhelper.DiffBool(t, "empty", "mean is NaN",
+		math.IsNaN(float64(s.Mean())), true)
+	testhelper.DiffBool(t, "empty", "variance is NaN",
+		math.IsNaN(float64(s.Variance())), true)
+
+	_, ok := s.Min()
+	testhelper.DiffBool(t, "empty", "min ok", ok, false)
+
+	s.Add(1)
+	testhelper.DiffBool(t, "one value", "variance is NaN",
+		math.IsNaN(float64(s.Variance())), true)
+	testhelper.DiffFloat(t, "one value", "pop variance",
+		s.PopVariance(), 0, 0)
+
+	s.Remove(1)
+	testhelper.DiffInt(t, "all removed", "count", s.Count(), 0)
+}
+
+func TestStatsNaN(t *testing.T) {
+	nan := math.NaN()
+	vals := []float64{1, 2, nan, 3}
+
+	testCases := []struct {
+		testhelper.ID
+		policy    mathutil.NaNPolicy
+		expNaN    bool
+		expMinNaN bool
+		expMaxNaN bool
+	}{
+		{
+			ID:        testhelper.MkID("propagate"),
+			policy:    mathutil.NaNPropagate,
+			expNaN:    true,
+			expMinNaN: true,
+			expMaxNaN: true,
+		},
+		{
+			ID:     testhelper.MkID("ignore"),
+			policy: mathutil.NaNIgnore,
+		},
+		{
+			ID:        testhelper.MkID("lowest"),
+			policy:    mathutil.NaNLowest,
+			expMinNaN: true,
+		},
+		{
+			ID:        testhelper.MkID("highest"),
+			policy:    mathutil.NaNHighest,
+			expMaxNaN: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		s := mathutil.NewStats[float64](tc.policy)
+		s.AddAll(vals...)
+
+		testhelper.DiffInt(t, tc.IDStr(), "count", s.Count(), 3)
+		testhelper.DiffInt(t, tc.IDStr(), "NaN count", s.NaNCount(), 1)
+		testhelper.DiffBool(t, tc.IDStr(), "mean is NaN",
+			math.IsNaN(s.Mean()), tc.expNaN)
+
+		if !tc.expNaN {
+			testhelper.DiffFloat(t, tc.IDStr(), "mean", s.Mean(), 2, 0)
+		}
+
+		minVal, _ := s.Min()
+		testhelper.DiffBool(t, tc.IDStr(), "min is NaN",
+			math.IsNaN(minVal), tc.expMinNaN)
+
+		maxVal, _ := s.Max()
+		testhelper.DiffBool(t, tc.IDStr(), "max is NaN",
+			math.IsNaN(maxVal), tc.expMaxNaN)
+	}
+}
@@ -0,0 +1,311 @@
+package mathutil
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/constraints"
+)
+
+// pairwiseBlockSize is the length below which pairwise summation adds the
+// values directly
+const pairwiseBlockSize = 128
+
+// SumAlgorithm selects the algorithm used by Sum to add up the values.
+type SumAlgorithm int
+
+// These are the available summation algorithms. They trade speed for
+// accuracy; in order of increasing accuracy (and cost) they are SumNaive,
+// SumPairwise, SumKahan and SumNeumaier. For a correctly rounded result
+// use SumExact.
+const (
+	// SumNeumaier uses Neumaier's improvement of Kahan's algorithm which
+	// also compensates when a value being added is larger in magnitude than
+	// the running sum.
+	SumNeumaier SumAlgorithm = iota
+	// SumKahan uses Kahan's compensated summation. The error is bounded
+	// independently of the number of values.
+	SumKahan
+	// SumPairwise recursively sums the two halves of the values. The error
+	// grows with the logarithm of the number of values; it is only a
+	// little slower than SumNaive.
+	SumPairwise
+	// SumNaive adds the values in order with no compensation. The error
+	// can grow in proportion to the number of values.
+	SumNaive
+)
+
+// String returns a string value for the SumAlgorithm
+func (a SumAlgorithm) String() string {
+	switch a {
+	case SumNeumaier:
+		return "SumNeumaier"
+	case SumKahan:
+		return "SumKahan"
+	case SumPairwise:
+		return "SumPairwise"
+	case SumNaive:
+		return "SumNaive"
+	}
+
+	return fmt.Sprintf("SumAlgorithm(%d)", int(a))
+}
+
+// Sum returns the sum of the values calculated using the given algorithm.
+// The arithmetic is performed in the precision of the values. It will
+// panic if the algorithm is not known.
+func Sum[F constraints.Float](vals []F, alg SumAlgorithm) F {
+	switch alg {
+	case SumNeumaier:
+		var ka KahanAccumulator[F]
+		ka.AddAll(vals...)
+
+		return ka.Sum()
+	case SumKahan:
+		return sumKahan(vals)
+	case SumPairwise:
+		return sumPairwise(vals)
+	case SumNaive:
+		return sumNaive(vals)
+	}
+
+	panic(fmt.Sprintf("Invalid summation algorithm: %s", alg))
+}
+
+// sumNaive returns the sum of the values added in order
+func sumNaive[F constraints.Float](vals []F) F {
+	var sum F
+	for _, v := range vals {
+		sum += v
+	}
+
+	return sum
+}
+
+// sumKahan returns the sum of the values using Kahan's algorithm
+func sumKahan[F constraints.Float](vals []F) F {
+	if sum, ok := nonFiniteSum(vals); ok {
+		return F(sum) // the compensation would make an infinite sum NaN
+	}
+
+	var sum, c F
+
+	for _, v := range vals {
+		y := v - c
+		t := sum + y
+		c = (t - sum) - y
+		sum = t
+	}
+
+	return sum
+}
+
+// sumPairwise returns the sum of the values using pairwise summation
+func sumPairwise[F constraints.Float](vals []F) F {
+	if len(vals) <= pairwiseBlockSize {
+		return sumNaive(vals)
+	}
+
+	mid := len(vals) / 2 //nolint:mnd
+
+	return sumPairwise(vals[:mid]) + sumPairwise(vals[mid:])
+}
+
+// KahanAccumulator accumulates a compensated sum of a stream of values. It
+// uses Neumaier's variant of Kahan summation (sometimes called
+// Kahan-Babuška summation) so the sum is accurate even if a value being
+// added is larger in magnitude than the running total. The zero value is
+// ready to use.
+type KahanAccumulator[F constraints.Float] struct {
+	sum F
+	c   F
+}
+
+// Add adds the value to the sum
+func (ka *KahanAccumulator[F]) Add(v F) {
+	t := ka.sum + v
+
+	if math.Abs(float64(ka.sum)) >= math.Abs(float64(v)) {
+		ka.c += (ka.sum - t) + v
+	} else {
+		ka.c += (v - t) + ka.sum
+	}
+
+	ka.sum = t
+}
+
+// AddAll adds each of the values to the sum
+func (ka *KahanAccumulator[F]) AddAll(vals ...F) {
+	for _, v := range vals {
+		ka.Add(v)
+	}
+}
+
+// Sum returns the compensated sum of the values added so far
+func (ka *KahanAccumulator[F]) Sum() F {
+	if math.IsInf(float64(ka.sum), 0) {
+		return ka.sum // the compensation would be NaN
+	}
+
+	return ka.sum + ka.c
+}
+
+// Reset sets the sum back to zero
+func (ka *KahanAccumulator[F]) Reset() {
+	ka.sum, ka.c = 0, 0
+}
+
+// nonFiniteSum returns the sum of the values if any of them are infinite or
+// NaN and true, otherwise it returns false. The sum is NaN if any value is
+// NaN or if there are infinities of both signs.
+func nonFiniteSum[F constraints.Float](vals []F) (float64, bool) {
+	posInf, negInf, nan := false, false, false
+
+	for _, v := range vals {
+		switch x := float64(v); {
+		case math.IsNaN(x):
+			nan = true
+		case math.IsInf(x, 1):
+			posInf = true
+		case math.IsInf(x, -1):
+			negInf = true
+		}
+	}
+
+	switch {
+	case nan || (posInf && negInf):
+		return math.NaN(), true
+	case posInf:
+		return math.Inf(1), true
+	case negInf:
+		return math.Inf(-1), true
+	}
+
+	return 0, false
+}
+
+// SumExact returns the correctly rounded sum of the values; that is, the
+// exact sum of the values rounded to the nearest float64 and then converted
+// to the type of the values. It uses Shewchuk's algorithm (as used by
+// Python's math.fsum) which keeps the exact sum as a list of
+// non-overlapping partial sums so it is slower than Sum.
+//
+// If any value is NaN or if there are infinities of both signs the result
+// is NaN. If an intermediate sum overflows the result is infinite.
+func SumExact[F constraints.Float](vals []F) F {
+	if sum, ok := nonFiniteSum(vals); ok {
+		return F(sum)
+	}
+
+	partials := []float64{}
+
+	for _, v := range vals {
+		x := float64(v)
+
+		i := 0
+		for _, y := range partials {
+			if math.Abs(x) < math.Abs(y) {
+				x, y = y, x
+			}
+
+			hi := x + y
+			lo := y - (hi - x)
+
+			if lo != 0 {
+				partials[i] = lo
+				i++
+			}
+
+			x = hi
+		}
+
+		if math.IsInf(x, 0) {
+			return F(x)
+		}
+
+		partials = append(partials[:i], x)
+	}
+
+	return F(roundPartials(partials))
+}
+
+// roundPartials returns the sum of the non-overlapping partial sums,
+// correctly rounded. The partials are in increasing order of magnitude.
+func roundPartials(partials []float64) float64 {
+	n := len(partials)
+	if n == 0 {
+		return 0
+	}
+
+	n--
+	hi := partials[n]
+	lo := 0.0
+
+	for n > 0 {
+		x := hi
+		n--
+		y := partials[n]
+		hi = x + y
+		lo = y - (hi - x)
+
+		if lo != 0 {
+			break
+		}
+	}
+
+	// If the remainder is exactly half an ulp and the next partial has the
+	// same sign then the sum must be rounded away from hi
+	if n > 0 &&
+		((lo < 0 && partials[n-1] < 0) || (lo > 0 && partials[n-1] > 0)) {
+		y := lo * 2 //nolint:mnd
+		x := hi + y
+
+		if y == x-hi {
+			hi = x
+		}
+	}
+
+	return hi
+}
+
+// twoSum returns the sum of a and b and the rounding error of that sum
+func twoSum(a, b float64) (sum, err float64) {
+	sum = a + b
+	bb := sum - a
+	err = (a - (sum - bb)) + (b - bb)
+
+	return sum, err
+}
+
+// Dot returns the dot product of a and b (the sum of the products of their
+// corresponding values). It uses the Dot2 algorithm of Ogita, Rump and
+// Oishi which calculates the products exactly (using math.FMA) and sums
+// them with compensation so the result is as accurate as if it had been
+// calculated in twice the precision of a float64 and then rounded. It
+// will panic if the slices are not the same length.
+func Dot[F constraints.Float](a, b []F) F {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("the slices must be the same length (%d != %d)",
+			len(a), len(b)))
+	}
+
+	var sum, c float64
+
+	for i, va := range a {
+		x, y := float64(va), float64(b[i])
+
+		p := float64(x * y) // the conversion prevents a fused multiply-add
+		pErr := math.FMA(x, y, -p)
+
+		var sErr float64
+
+		sum, sErr = twoSum(sum, p)
+		c += pErr + sErr
+	}
+
+	if math.IsInf(sum, 0) || math.IsNaN(sum) {
+		return F(sum) // the error terms are meaningless
+	}
+
+	return F(sum + c)
+}
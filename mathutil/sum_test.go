@@ -0,0 +1,252 @@
+package mathutil_test
+
+import (
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/nickwells/mathutil.mod/v2/mathutil"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// sumAlgorithms lists the summation algorithms in the order of the
+// expected values in TestSum
+var sumAlgorithms = []mathutil.SumAlgorithm{
+	mathutil.SumNaive,
+	mathutil.SumPairwise,
+	mathutil.SumKahan,
+	mathutil.SumNeumaier,
+}
+
+// diffSum compares the sums which must be identical or both be NaN
+func diffSum(t *testing.T, id, name string, act, exp float64) {
+	t.Helper()
+
+	if math.IsNaN(act) && math.IsNaN(exp) {
+		return
+	}
+
+	testhelper.DiffFloat(t, id, name, act, exp, 0)
+}
+
+func TestSum(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		vals []float64
+		// expVals gives the expected value for each of the sumAlgorithms
+		expVals  []float64
+		expExact float64
+	}{
+		{
+			ID:       testhelper.MkID("empty"),
+			expVals:  []float64{0, 0, 0, 0},
+			expExact: 0,
+		},
+		{
+			ID:   testhelper.MkID("ten tenths"),
+			vals: slices.Repeat([]float64{0.1}, 10),
+			expVals: []float64{
+				0.9999999999999999, 0.9999999999999999, 1, 1,
+			},
+			expExact: 1,
+		},
+		{
+			ID:       testhelper.MkID("large cancelling values"),
+			vals:     []float64{1, 1e100, 1, -1e100},
+			expVals:  []float64{0, 0, 0, 2},
+			expExact: 2,
+		},
+		{
+			ID:       testhelper.MkID("correct rounding"),
+			vals:     []float64{1e-16, 1, 1e16},
+			expVals:  []float64{1e16, 1e16, 1e16, 1e16},
+			expExact: 1.0000000000000002e16,
+		},
+		{
+			ID:       testhelper.MkID("half-way rounding"),
+			vals:     []float64{1, 1e-16, 1e-16},
+			expVals:  []float64{1, 1, 1.0000000000000002, 1.0000000000000002},
+			expExact: 1.0000000000000002,
+		},
+		{
+			ID:   testhelper.MkID("infinity"),
+			vals: []float64{1, math.Inf(1), 1},
+			expVals: []float64{
+				math.Inf(1), math.Inf(1), math.Inf(1), math.Inf(1),
+			},
+			expExact: math.Inf(1),
+		},
+		{
+			ID:   testhelper.MkID("opposite infinities"),
+			vals: []float64{math.Inf(-1), math.Inf(1)},
+			expVals: []float64{
+				math.NaN(), math.NaN(), math.NaN(), math.NaN(),
+			},
+			expExact: math.NaN(),
+		},
+	}
+
+	for _, tc := range testCases {
+		for i, alg := range sumAlgorithms {
+			diffSum(t, tc.IDStr(), alg.String(),
+				mathutil.Sum(tc.vals, alg), tc.expVals[i])
+		}
+
+		diffSum(t, tc.IDStr(), "SumExact",
+			mathutil.SumExact(tc.vals), tc.expExact)
+	}
+}
+
+// exactSum returns the exact sum of the values rounded to a float64
+func exactSum(vals []float64) float64 {
+	sum := new(big.Float).SetPrec(2048) //nolint:mnd
+
+	for _, v := range vals {
+		sum.Add(sum, big.NewFloat(v))
+	}
+
+	f, _ := sum.Float64()
+
+	return f
+}
+
+func TestSumExactRandom(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10)) //nolint:gosec
+
+	for trial := range 100 {
+		n := 1 + r.IntN(200)
+
+		vals := make([]float64, 0, n)
+		for range n {
+			v := r.NormFloat64() * math.Pow(10, float64(r.IntN(40)-20))
+			vals = append(vals, v, -v*(1+r.Float64()*1e-10))
+		}
+
+		r.Shuffle(len(vals), func(i, j int) {
+			vals[i], vals[j] = vals[j], vals[i]
+		})
+
+		exp := exactSum(vals)
+
+		id := testhelper.MkID("random trial")
+		if act := mathutil.SumExact(vals); act != exp {
+			t.Log(id.IDStr())
+			t.Errorf("\t: trial %d: SumExact: %g != %g", trial, act, exp)
+		}
+
+		if act := mathutil.Sum(vals, mathutil.SumNeumaier); !mathutil.IsClose(
+			act, exp, mathutil.IsCloseAbsTol(1e-20)) {
+			t.Log(id.IDStr())
+			t.Errorf("\t: trial %d: SumNeumaier: %g != %g", trial, act, exp)
+		}
+	}
+}
+
+func TestSumFloat32(t *testing.T) {
+	vals := slices.Repeat([]float32{0.1}, 1000)
+
+	testhelper.DiffFloat(t, "float32", "SumNeumaier",
+		mathutil.Sum(vals, mathutil.SumNeumaier), 100, 0)
+	testhelper.DiffFloat(t, "float32", "SumExact",
+		mathutil.SumExact(vals), float32(float64(float32(0.1))*1000), 0)
+
+	if naive := mathutil.Sum(vals, mathutil.SumNaive); naive == 100 {
+		t.Error("float32: the naive sum was expected to drift")
+	}
+}
+
+func TestKahanAccumulator(t *testing.T) {
+	var ka mathutil.KahanAccumulator[float64]
+
+	for range 10 {
+		ka.Add(0.1)
+	}
+
+	testhelper.DiffFloat(t, "KahanAccumulator", "tenths", ka.Sum(), 1, 0)
+
+	ka.AddAll(1e100, 1, -1e100)
+	testhelper.DiffFloat(t, "KahanAccumulator", "cancelling",
+		ka.Sum(), 2, 0)
+
+	ka.Reset()
+	testhelper.DiffFloat(t, "KahanAccumulator", "reset", ka.Sum(), 0, 0)
+
+	ka.AddAll(math.Inf(-1), 1)
+	testhelper.DiffFloat(t, "KahanAccumulator", "infinity",
+		ka.Sum(), math.Inf(-1), 0)
+}
+
+func TestDot(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpPanic
+		a, b   []float64
+		expVal float64
+	}{
+		{
+			ID:     testhelper.MkID("empty"),
+			expVal: 0,
+		},
+		{
+			ID:     testhelper.MkID("simple"),
+			a:      []float64{1, 2, 3},
+			b:      []float64{4, 5, 6},
+			expVal: 32,
+		},
+		{
+			ID:     testhelper.MkID("cancellation"),
+			a:      []float64{1e100, 1, -1e100},
+			b:      []float64{1, 1, 1},
+			expVal: 1,
+		},
+		{
+			ID:     testhelper.MkID("inexact products"),
+			a:      []float64{1 + 0x1p-30, 1 - 0x1p-30},
+			b:      []float64{1 + 0x1p-30, -(1 - 0x1p-30)},
+			expVal: 0x1p-28,
+		},
+		{
+			ID:     testhelper.MkID("infinity"),
+			a:      []float64{math.Inf(1), 1},
+			b:      []float64{2, 3},
+			expVal: math.Inf(1),
+		},
+		{
+			ID: testhelper.MkID("different lengths"),
+			ExpPanic: testhelper.MkExpPanic(
+				"the slices must be the same length (2 != 1)"),
+			a: []float64{1, 2},
+			b: []float64{1},
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			testhelper.DiffFloat(t, tc.IDStr(), "dot product",
+				mathutil.Dot(tc.a, tc.b), tc.expVal, 0)
+		})
+		testhelper.CheckExpPanic(t, panicked, panicVal, tc)
+	}
+}
+
+func TestSumAlgorithmString(t *testing.T) {
+	testhelper.DiffString(t, "SumAlgorithm", "known",
+		mathutil.SumPairwise.String(), "SumPairwise")
+	testhelper.DiffString(t, "SumAlgorithm", "unknown",
+		mathutil.SumAlgorithm(99).String(), "SumAlgorithm(99)")
+
+	panicked, panicVal := testhelper.PanicSafe(func() {
+		mathutil.Sum([]float64{1}, mathutil.SumAlgorithm(99))
+	})
+	testhelper.CheckExpPanic(t, panicked, panicVal,
+		struct {
+			testhelper.ID
+			testhelper.ExpPanic
+		}{
+			ID: testhelper.MkID("bad algorithm"),
+			ExpPanic: testhelper.MkExpPanic(
+				"Invalid summation algorithm: SumAlgorithm(99)"),
+		})
+}